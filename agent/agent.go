@@ -2,6 +2,8 @@ package agent
 
 import (
 	"context"
+
+	"github.com/cloudwego/eino/schema"
 )
 
 // StreamChunk represents a data chunk for streaming output
@@ -21,4 +23,7 @@ type Agent interface {
 	ChatWithCallback(ctx context.Context, prompt string, callback func(interface{})) (string, error)
 	// ChatStream performs streaming conversation, handles streaming output through chunk callback
 	ChatStream(ctx context.Context, prompt string, chunkCallback func(*StreamChunk), toolCallback func(interface{})) error
+	// ChatStreamWithHistory performs streaming conversation over a full message history (without system prompt),
+	// returns every message produced in the turn, final reply last, so callers can append them to their history
+	ChatStreamWithHistory(ctx context.Context, history []*schema.Message, chunkCallback func(*StreamChunk), toolCallback func(interface{})) ([]*schema.Message, error)
 }
@@ -17,12 +17,28 @@ func NewFactory(cfg *config.Config) *Factory {
 
 // CreateAgent creates Agent based on name
 func (f *Factory) CreateAgent(name string) (Agent, error) {
+	return f.CreateAgentWithModel(name, "")
+}
+
+// CreateAgentWithModel creates Agent based on name, running it with modelName instead of its configured model.
+// An empty modelName keeps the configured model, falling back to default_model.
+func (f *Factory) CreateAgentWithModel(name, modelName string) (Agent, error) {
 	// Get Agent configuration
 	agentCfg, ok := f.cfg.Agents[name]
 	if !ok {
 		return nil, fmt.Errorf("Agent configuration does not exist: %s", name)
 	}
 
+	// Resolve model
+	if modelName == "" {
+		modelName = agentCfg.Model
+	}
+	modelName, err := f.cfg.ResolveModel(modelName)
+	if err != nil {
+		return nil, fmt.Errorf("Agent %s: %w", name, err)
+	}
+	agentCfg.Model = modelName
+
 	// Create ReactAgent
 	agent := NewReactAgent(name, &agentCfg)
 	return agent, nil
@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tk103331/eino-cli/config"
+)
+
+func TestCreateAgentWithModel(t *testing.T) {
+	cfg := &config.Config{
+		DefaultModel: "fast",
+		Models: map[string]config.Model{
+			"fast":  {Provider: "p", Model: "fast"},
+			"smart": {Provider: "p", Model: "smart"},
+		},
+		Agents: map[string]config.Agent{
+			"pinned":   {Model: "smart"},
+			"default":  {},
+			"dangling": {Model: "missing"},
+		},
+	}
+	tests := []struct {
+		name      string
+		agent     string
+		model     string
+		wantModel string
+		wantErr   string
+	}{
+		{name: "configured model", agent: "pinned", wantModel: "smart"},
+		{name: "override", agent: "pinned", model: "fast", wantModel: "fast"},
+		{name: "default_model", agent: "default", wantModel: "fast"},
+		{name: "override of a missing model", agent: "dangling", model: "smart", wantModel: "smart"},
+		{name: "missing model", agent: "dangling", wantErr: "Agent dangling: model configuration does not exist: missing"},
+		{name: "unknown override", agent: "pinned", model: "missing", wantErr: "model configuration does not exist: missing"},
+		{name: "unknown agent", agent: "other", wantErr: "Agent configuration does not exist: other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewFactory(cfg).CreateAgentWithModel(tt.agent, tt.model)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := a.(*ReactAgent).config.Model; got != tt.wantModel {
+				t.Errorf("expected model %s, got %s", tt.wantModel, got)
+			}
+		})
+	}
+	// The configuration of the agent is left untouched
+	if got := cfg.Agents["pinned"].Model; got != "smart" {
+		t.Errorf("expected the agent configuration to keep model smart, got %s", got)
+	}
+}
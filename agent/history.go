@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"sync"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Conversation holds the message history of a multi-turn session.
+// The system prompt is not stored here, agents prepend their own on every turn.
+type Conversation struct {
+	mu       sync.RWMutex
+	messages []*schema.Message
+}
+
+// NewConversation creates a new Conversation, optionally seeded with existing messages
+func NewConversation(messages ...*schema.Message) *Conversation {
+	c := &Conversation{}
+	c.Append(messages...)
+	return c
+}
+
+// Append adds messages to the end of the conversation
+func (c *Conversation) Append(messages ...*schema.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, msg := range messages {
+		if msg != nil {
+			c.messages = append(c.messages, msg)
+		}
+	}
+}
+
+// Messages returns a copy of the conversation messages
+func (c *Conversation) Messages() []*schema.Message {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	messages := make([]*schema.Message, len(c.messages))
+	copy(messages, c.messages)
+	return messages
+}
+
+// Len returns the number of messages in the conversation
+func (c *Conversation) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.messages)
+}
+
+// Truncate drops every message after the first n, used to roll back a failed turn
+func (c *Conversation) Truncate(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if n < 0 {
+		n = 0
+	}
+	if n < len(c.messages) {
+		c.messages = c.messages[:n]
+	}
+}
+
+// Reset clears the conversation
+func (c *Conversation) Reset() {
+	c.Truncate(0)
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"regexp"
 	"strings"
+	"sync"
 	"unicode"
 
 	"github.com/cloudwego/eino/callbacks"
@@ -21,6 +22,7 @@ import (
 	"github.com/tk103331/eino-cli/logger"
 	"github.com/tk103331/eino-cli/mcp"
 	"github.com/tk103331/eino-cli/models"
+	"github.com/tk103331/eino-cli/tools/custom"
 )
 
 // ReactAgent implements Agent using React pattern from cloudwego/eino library
@@ -357,16 +359,28 @@ func isMostlyChinese(s string) bool {
 
 // ToolCallInfo represents structured tool call information
 type ToolCallInfo struct {
-	Type      string // "start", "end", "error"
+	Type      string // "start", "progress", "end", "error"
 	Name      string
 	Arguments string
 	Result    string
 	Error     string
 }
 
+// IsInternalNode determines if the name refers to an internal ReAct graph node rather than an actual tool call
+func IsInternalNode(name string) bool {
+	// ChatModel is the model node, Tools is the ReAct tools orchestrator node
+	return name == "ChatModel" || name == "Tools"
+}
+
 // ToolCallCallback custom callback handler for capturing tool call information
 type ToolCallCallback struct {
 	callback func(interface{})
+	pending  sync.WaitGroup // Streamed tool results still being collected
+}
+
+// Wait blocks until the results of streaming tools have been sent, so that they are reported within their turn
+func (t *ToolCallCallback) Wait() {
+	t.pending.Wait()
 }
 
 // OnStart callback when node starts
@@ -474,6 +488,18 @@ func (t *ToolCallCallback) OnStart(ctx context.Context, info *callbacks.RunInfo,
 			Name:      info.Name,
 			Arguments: args,
 		})
+
+		// Let long-running tools report their output while they run
+		if !IsInternalNode(info.Name) {
+			name := info.Name
+			ctx = custom.WithProgress(ctx, func(line string) {
+				t.callback(ToolCallInfo{
+					Type:   "progress",
+					Name:   name,
+					Result: line,
+				})
+			})
+		}
 	}
 	return ctx
 }
@@ -533,8 +559,51 @@ func (t *ToolCallCallback) OnStartWithStreamInput(ctx context.Context, info *cal
 	return ctx
 }
 
-// OnEndWithStreamOutput callback when stream output ends
+// OnEndWithStreamOutput callback when stream output ends, streamed tool results are collected and sent
+// like in OnEnd once the stream is done; Wait blocks until then
 func (t *ToolCallCallback) OnEndWithStreamOutput(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+	if t.callback == nil || info.Name == "" || IsInternalNode(info.Name) {
+		output.Close()
+		return ctx
+	}
+
+	t.pending.Add(1)
+	go func() {
+		defer t.pending.Done()
+		defer output.Close()
+
+		var result strings.Builder
+		for {
+			chunk, err := output.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				// Stream errors are reported to OnError by the tools node
+				return
+			}
+			switch c := chunk.(type) {
+			case string:
+				result.WriteString(c)
+			case *tool.CallbackOutput:
+				result.WriteString(c.Response)
+			default:
+				result.WriteString(fmt.Sprintf("%v", c))
+			}
+		}
+
+		text := result.String()
+		if len(text) > 200 {
+			text = text[:197] + "..."
+		}
+
+		logger.Debug("AGENT", fmt.Sprintf("Sending callback for %s end", info.Name))
+		t.callback(ToolCallInfo{
+			Type:   "end",
+			Name:   info.Name,
+			Result: text,
+		})
+	}()
 	return ctx
 }
 
@@ -623,6 +692,8 @@ func (r *ReactAgent) Run(prompt string) error {
 						fmt.Printf("   📝 Arguments: %s\n", formattedArgs)
 					}
 				}
+			case "progress":
+				fmt.Printf("   │ %s\n", info.Result)
 			case "end":
 				if info.Name == "ChatModel" {
 					fmt.Printf("   ✅ ChatModel response generated\n")
@@ -699,6 +770,7 @@ func (r *ReactAgent) ChatWithCallback(ctx context.Context, prompt string, callba
 
 	// Create tool call callback handler
 	toolCallback := &ToolCallCallback{callback: callback}
+	defer toolCallback.Wait()
 
 	// Use Stream method for streaming call and add callback handler via agent.WithComposeOptions
 	sr, err := r.agent.Stream(ctx, messages, agent.WithComposeOptions(compose.WithCallbacks(toolCallback)))
@@ -732,44 +804,57 @@ func (r *ReactAgent) ChatWithCallback(ctx context.Context, prompt string, callba
 
 // ChatStream performs streaming conversation, handles streaming output via chunk callback
 func (r *ReactAgent) ChatStream(ctx context.Context, prompt string, chunkCallback func(*StreamChunk), toolCallback func(interface{})) error {
+	_, err := r.ChatStreamWithHistory(ctx, []*schema.Message{schema.UserMessage(prompt)}, chunkCallback, toolCallback)
+	return err
+}
+
+// ChatStreamWithHistory performs streaming conversation over the given history,
+// returns every message produced in the turn: assistant tool calls, tool results and the final reply last
+func (r *ReactAgent) ChatStreamWithHistory(ctx context.Context, history []*schema.Message, chunkCallback func(*StreamChunk), toolCallback func(interface{})) ([]*schema.Message, error) {
 	if r.agent == nil {
 		if err := r.Init(); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	// Create messages
-	messages := []*schema.Message{
-		schema.SystemMessage(r.config.System),
-		schema.UserMessage(prompt),
+	// Create messages, system prompt first followed by the conversation history
+	messages := make([]*schema.Message, 0, len(history)+1)
+	if r.config.System != "" {
+		messages = append(messages, schema.SystemMessage(r.config.System))
 	}
+	messages = append(messages, history...)
 
 	// Log the messages for debugging
-	logger.Info("AGENT", "Starting ChatStream with messages:")
+	logger.Info("AGENT", fmt.Sprintf("Starting ChatStream with %d history messages", len(history)))
 	logger.Debug("AGENT", fmt.Sprintf("System prompt: %s", r.config.System))
-	logger.Debug("AGENT", fmt.Sprintf("User prompt: %s", prompt))
+	if len(history) > 0 {
+		logger.Debug("AGENT", fmt.Sprintf("Latest message: %s", history[len(history)-1].Content))
+	}
 
 	// Create tool call callback handler
 	var toolCallCallback *ToolCallCallback
 	if toolCallback != nil {
 		toolCallCallback = &ToolCallCallback{callback: toolCallback}
+		// Runs after the stream is closed, no tool result arrives after the turn
+		defer toolCallCallback.Wait()
 		logger.Debug("AGENT", "Tool callback configured")
 	} else {
 		logger.Debug("AGENT", "No tool callback provided")
 	}
 
-	// Use Stream method for streaming call
-	var sr *schema.StreamReader[*schema.Message]
-	var err error
+	// Collect the messages of the turn so callers can keep tool calls and results in their history
+	futureOption, future := react.WithMessageFuture()
+	options := []agent.AgentOption{futureOption}
 
+	// Use Stream method for streaming call
 	logger.Info("AGENT", "Calling agent.Stream method")
 	if toolCallCallback != nil {
 		logger.Debug("AGENT", "Using stream with callbacks")
-		sr, err = r.agent.Stream(ctx, messages, agent.WithComposeOptions(compose.WithCallbacks(toolCallCallback)))
+		options = append(options, agent.WithComposeOptions(compose.WithCallbacks(toolCallCallback)))
 	} else {
 		logger.Debug("AGENT", "Using stream without callbacks")
-		sr, err = r.agent.Stream(ctx, messages)
 	}
+	sr, err := r.agent.Stream(ctx, messages, options...)
 	if err != nil {
 		logger.Error("AGENT", fmt.Sprintf("Stream call failed: %v", err))
 		if chunkCallback != nil {
@@ -778,7 +863,7 @@ func (r *ReactAgent) ChatStream(ctx context.Context, prompt string, chunkCallbac
 				Content: fmt.Sprintf("Stream failed: %v", err),
 			})
 		}
-		return fmt.Errorf("Stream failed: %w", err)
+		return nil, fmt.Errorf("Stream failed: %w", err)
 	}
 	defer sr.Close()
 
@@ -786,6 +871,8 @@ func (r *ReactAgent) ChatStream(ctx context.Context, prompt string, chunkCallbac
 
 	// Read streaming response
 	messageCount := 0
+	var content strings.Builder
+	var usage *schema.TokenUsage
 	for {
 		msg, err := sr.Recv()
 		if err != nil {
@@ -807,7 +894,7 @@ func (r *ReactAgent) ChatStream(ctx context.Context, prompt string, chunkCallbac
 					Content: fmt.Sprintf("failed to receive stream message: %v", err),
 				})
 			}
-			return fmt.Errorf("failed to receive stream message: %w", err)
+			return nil, fmt.Errorf("failed to receive stream message: %w", err)
 		}
 
 		messageCount++
@@ -828,6 +915,11 @@ func (r *ReactAgent) ChatStream(ctx context.Context, prompt string, chunkCallbac
 			}
 		}
 
+		content.WriteString(msg.Content)
+		if msg.ResponseMeta != nil && msg.ResponseMeta.Usage != nil {
+			usage = msg.ResponseMeta.Usage
+		}
+
 		// Send content chunk
 		if chunkCallback != nil && msg.Content != "" {
 			chunkCallback(&StreamChunk{
@@ -837,7 +929,40 @@ func (r *ReactAgent) ChatStream(ctx context.Context, prompt string, chunkCallbac
 		}
 	}
 
-	return nil
+	turn, err := collectTurnMessages(future)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect turn messages: %w", err)
+	}
+	if len(turn) == 0 {
+		reply := schema.AssistantMessage(content.String(), nil)
+		if usage != nil {
+			reply.ResponseMeta = &schema.ResponseMeta{Usage: usage}
+		}
+		turn = append(turn, reply)
+	}
+	logger.Debug("AGENT", fmt.Sprintf("Turn produced %d messages", len(turn)))
+	return turn, nil
+}
+
+// collectTurnMessages concatenates the message streams the agent produced in a turn,
+// must be called after the agent output stream has been fully read
+func collectTurnMessages(future react.MessageFuture) ([]*schema.Message, error) {
+	var turn []*schema.Message
+	iter := future.GetMessageStreams()
+	for {
+		stream, ok, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return turn, nil
+		}
+		msg, err := schema.ConcatMessageStream(stream)
+		if err != nil {
+			return nil, err
+		}
+		turn = append(turn, msg)
+	}
 }
 
 // createModel creates model
@@ -881,15 +1006,17 @@ func (r *ReactAgent) createToolsConfig() (compose.ToolsNodeConfig, error) {
 			return toolsConfig, fmt.Errorf("tool configuration does not exist: %s", toolName)
 		}
 
-		// Create tool instance
-		toolInstance, err := createTool(toolName, toolCfg)
+		// Create tool instances, a bundle adds all of its tools
+		toolInstances, err := createTools(toolName, toolCfg)
 		if err != nil {
 			logger.Error("AGENT", fmt.Sprintf("Failed to create tool %s: %v", toolName, err))
 			return toolsConfig, err
 		}
 
-		logger.Debug("AGENT", fmt.Sprintf("Added regular tool: %s", toolName))
-		toolsConfig.Tools = append(toolsConfig.Tools, toolInstance)
+		logger.Debug("AGENT", fmt.Sprintf("Added regular tool: %s (%d tools)", toolName, len(toolInstances)))
+		for _, toolInstance := range toolInstances {
+			toolsConfig.Tools = append(toolsConfig.Tools, toolInstance)
+		}
 	}
 
 	// Add MCP tools
@@ -0,0 +1,246 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+	"github.com/tk103331/eino-cli/config"
+	"github.com/tk103331/eino-cli/models"
+	"github.com/tk103331/eino-cli/tools"
+)
+
+func init() {
+	tools.Register("test_prefix", newPrefixTool, "prefix")
+	tools.Register("test_stream", newStreamTool, "prefix")
+}
+
+// prefixTool answers with its configured prefix followed by the call arguments
+type prefixTool struct {
+	name   string
+	prefix string
+}
+
+func newPrefixTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
+	prefix := cfg.Config["prefix"]
+	return &prefixTool{name: name, prefix: prefix.String()}, nil
+}
+
+func (t *prefixTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: t.name, Desc: "Echoes the arguments after " + t.prefix}, nil
+}
+
+func (t *prefixTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	return t.prefix + argumentsInJSON, nil
+}
+
+// streamTool streams its configured prefix followed by the call arguments in two chunks
+type streamTool struct {
+	prefixTool
+}
+
+func newStreamTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
+	prefix := cfg.Config["prefix"]
+	return &streamTool{prefixTool{name: name, prefix: prefix.String()}}, nil
+}
+
+func (t *streamTool) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (*schema.StreamReader[string], error) {
+	return schema.StreamReaderFromArray([]string{t.prefix, argumentsInJSON}), nil
+}
+
+// newTestAgent loads a configuration whose agent talks to the mock provider replaying the given interactions,
+// with the tools upper and lower answering with their own prefix
+func newTestAgent(t *testing.T, interactions []models.Interaction) *ReactAgent {
+	t.Helper()
+	dir := t.TempDir()
+	cassette := filepath.Join(dir, "cassette.json")
+	if err := (&models.Cassette{Interactions: interactions}).Save(cassette); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(dir, "config.yml")
+	data := fmt.Sprintf(`providers:
+  fake:
+    type: mock
+    cassette: %q
+models:
+  scripted:
+    provider: fake
+    model: scripted
+tools:
+  upper:
+    type: test_prefix
+    config:
+      prefix: "upper:"
+  lower:
+    type: test_prefix
+    config:
+      prefix: "lower:"
+  streamed:
+    type: test_stream
+    config:
+      prefix: "streamed:"
+agents:
+  helper:
+    model: scripted
+    system: You are a test agent.
+    tools: [upper, lower, streamed]
+`, cassette)
+	if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := NewFactory(cfg).CreateAgent("helper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a.(*ReactAgent)
+}
+
+func TestReactAgentChat(t *testing.T) {
+	tests := []struct {
+		name         string
+		interactions []models.Interaction
+		want         string
+		wantErr      bool
+	}{
+		{
+			name:         "reply",
+			interactions: []models.Interaction{{Response: &models.MockMessage{Content: "hello there"}}},
+			want:         "hello there",
+		},
+		{
+			name: "tool call",
+			interactions: []models.Interaction{
+				{Response: &models.MockMessage{ToolCalls: []models.MockToolCall{{Name: "upper", Arguments: `{"text":"hi"}`}}}},
+				{Match: `^upper:\{"text":"hi"\}$`, Response: &models.MockMessage{Content: "upper done"}},
+			},
+			want: "upper done",
+		},
+		{
+			name:         "model error",
+			interactions: []models.Interaction{{Error: "status code: 500"}},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newTestAgent(t, tt.interactions).Chat(context.Background(), "hello")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got reply %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected reply %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReactAgentToolRouting(t *testing.T) {
+	tests := []struct {
+		name string
+		tool string // Tool the model calls, the other one must not run
+	}{
+		{"upper", "upper"},
+		{"lower", "lower"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAgent(t, []models.Interaction{
+				{Response: &models.MockMessage{ToolCalls: []models.MockToolCall{{ID: "call_1", Name: tt.tool, Arguments: `{"text":"hi"}`}}}},
+				// Only replayed when the tool result reached the model
+				{Match: "^" + tt.tool + ":", Response: &models.MockMessage{Content: "all done", Chunks: []string{"all ", "done"}}},
+			})
+
+			var (
+				mu      sync.Mutex
+				content strings.Builder
+				started = make(map[string]bool)
+			)
+			turn, err := a.ChatStreamWithHistory(context.Background(), []*schema.Message{schema.UserMessage("hello")},
+				func(chunk *StreamChunk) {
+					if chunk.Type == "content" {
+						content.WriteString(chunk.Content)
+					}
+				},
+				func(info interface{}) {
+					if call, ok := info.(ToolCallInfo); ok && call.Type == "start" && !IsInternalNode(call.Name) {
+						mu.Lock()
+						started[call.Name] = true
+						mu.Unlock()
+					}
+				})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if content.String() != "all done" {
+				t.Errorf("expected streamed content %q, got %q", "all done", content.String())
+			}
+			if len(started) != 1 || !started[tt.tool] {
+				t.Errorf("expected only tool %s to start, got %v", tt.tool, started)
+			}
+			if len(turn) != 3 {
+				t.Fatalf("expected tool call, tool result and reply in the turn, got %d messages", len(turn))
+			}
+			if calls := turn[0].ToolCalls; len(calls) != 1 || calls[0].Function.Name != tt.tool {
+				t.Errorf("expected a call of %s first, got %+v", tt.tool, turn[0])
+			}
+			if turn[1].Role != schema.Tool || turn[1].ToolCallID != "call_1" || turn[1].Content != tt.tool+`:{"text":"hi"}` {
+				t.Errorf("expected the result of %s second, got %+v", tt.tool, turn[1])
+			}
+			if turn[2].Role != schema.Assistant || turn[2].Content != "all done" {
+				t.Errorf("expected the final reply last, got %+v", turn[2])
+			}
+		})
+	}
+}
+
+func TestReactAgentStreamedToolEnd(t *testing.T) {
+	a := newTestAgent(t, []models.Interaction{
+		{Response: &models.MockMessage{ToolCalls: []models.MockToolCall{{ID: "call_1", Name: "streamed", Arguments: `{"text":"hi"}`}}}},
+		{Match: `^streamed:\{"text":"hi"\}$`, Response: &models.MockMessage{Content: "all done"}},
+	})
+
+	var (
+		mu   sync.Mutex
+		ends []string
+	)
+	_, err := a.ChatStreamWithHistory(context.Background(), []*schema.Message{schema.UserMessage("hello")}, nil,
+		func(info interface{}) {
+			call, ok := info.(ToolCallInfo)
+			if !ok || call.Type != "end" || call.Name != "streamed" {
+				return
+			}
+			// A slow UI must still receive the result within the turn
+			time.Sleep(50 * time.Millisecond)
+			mu.Lock()
+			ends = append(ends, call.Result)
+			mu.Unlock()
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if want := `streamed:{"text":"hi"}`; len(ends) != 1 || ends[0] != want {
+		t.Fatalf("expected the result %q before the turn returned, got %q", want, ends)
+	}
+}
@@ -6,7 +6,7 @@ import (
 	"github.com/tk103331/eino-cli/tools"
 )
 
-// createTool creates tool instances
-func createTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
-	return tools.CreateTool(name, cfg)
+// createTools creates the tool instances configured under name, several for tool bundles
+func createTools(name string, cfg config.Tool) ([]tool.InvokableTool, error) {
+	return tools.CreateTools(name, cfg)
 }
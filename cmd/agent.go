@@ -8,6 +8,7 @@ import (
 	"github.com/cloudwego/eino/callbacks"
 	"github.com/spf13/cobra"
 	"github.com/tk103331/eino-cli/config"
+	"github.com/tk103331/eino-cli/session"
 	"github.com/tk103331/eino-cli/ui/agent"
 )
 
@@ -30,71 +31,113 @@ var agentCmd = &cobra.Command{
 		chatName, _ := cmd.Flags().GetString("chat")
 		modelName, _ := cmd.Flags().GetString("model")
 		toolsStr, _ := cmd.Flags().GetString("tools")
+		resumeID, _ := cmd.Flags().GetString("resume")
+
+		// Resuming a saved session takes precedence over other modes
+		if resumeID != "" {
+			return resumeSession(resumeID)
+		}
 
 		// Prioritize using agent mode
 		if agentName != "" {
-			// Verify if agent name exists
-			if _, ok := cfg.Agents[agentName]; !ok {
-				return fmt.Errorf("Agent configuration does not exist: %s", agentName)
-			}
-
-			// Create Agent interactive application
-			agentApp, err := agent.NewAgentApp(agentName)
-			if err != nil {
-				return fmt.Errorf("failed to create Agent application: %w", err)
-			}
-
-			// Run interactive interface
-			fmt.Printf("Starting interactive session with Agent %s...\n", agentName)
-			fmt.Println("Use 'q' or 'Ctrl+C' to exit")
-
-			if err := agentApp.Run(); err != nil {
-				return fmt.Errorf("failed to run interactive interface: %w", err)
-			}
-		} else if chatName != "" || modelName != "" {
-			// Use chat mode
-			var system string
-			var tools []string
-
-			if chatName != "" {
-				// Use chats preset
-				preset, ok := cfg.Chats[chatName]
-				if !ok {
-					return fmt.Errorf("chat preset does not exist: %s", chatName)
-				}
-				modelName = preset.Model
-				tools = append(tools, preset.Tools...)
-				system = preset.System
-			} else {
-				// Parse tool list
-				if toolsStr != "" {
-					tools = strings.Split(toolsStr, ",")
-					// Remove whitespace
-					for i, tool := range tools {
-						tools[i] = strings.TrimSpace(tool)
-					}
-				}
-				if modelName == "" {
-					return fmt.Errorf("must specify --model or --chat preset name")
-				}
-			}
-
-			// Create chat application
-			chatApp := agent.NewChatApp(modelName, tools, system)
-
-			// Run chat interface
-			fmt.Printf("Starting chat session with Model %s...\n", modelName)
-			if err := chatApp.Run(); err != nil {
-				return fmt.Errorf("failed to run chat interface: %w", err)
-			}
-		} else {
-			return fmt.Errorf("must specify --agent name or --chat/--model to start chat")
+			return startAgent(agentName, modelName)
 		}
 
-		return nil
+		// Use chat mode, falling back to default_model when neither a preset nor a model is given
+		return startChat(chatName, modelName, parseTools(toolsStr))
 	},
 }
 
+// startAgent starts an interactive session with an agent, modelName overrides the agent's model if set
+func startAgent(agentName, modelName string) error {
+	cfg := config.GetConfig()
+
+	// Verify if agent name exists
+	agentCfg, ok := cfg.Agents[agentName]
+	if !ok {
+		return fmt.Errorf("Agent configuration does not exist: %s", agentName)
+	}
+	if modelName == "" {
+		modelName = agentCfg.Model
+	}
+	modelName, err := cfg.ResolveModel(modelName)
+	if err != nil {
+		return fmt.Errorf("Agent %s: %w", agentName, err)
+	}
+
+	// Create Agent interactive application
+	sess := session.New(session.KindAgent, agentName, modelName)
+	agentApp, err := agent.NewAgentApp(agentName, sess)
+	if err != nil {
+		return fmt.Errorf("failed to create Agent application: %w", err)
+	}
+
+	// Run interactive interface
+	fmt.Printf("Starting interactive session with Agent %s (Model %s)...\n", agentName, modelName)
+	fmt.Println("Use 'q' or 'Ctrl+C' to exit")
+	fmt.Printf("Session ID: %s\n", sess.ID)
+
+	if err := agentApp.Run(); err != nil {
+		return fmt.Errorf("failed to run interactive interface: %w", err)
+	}
+	return nil
+}
+
+// startChat starts an interactive chat with a preset or a model.
+// Without a preset, tools are used as given and an empty modelName falls back to default_model.
+func startChat(chatName, modelName string, tools []string) error {
+	cfg := config.GetConfig()
+
+	var system string
+	if chatName != "" {
+		// Use chats preset
+		preset, ok := cfg.Chats[chatName]
+		if !ok {
+			return fmt.Errorf("chat preset does not exist: %s", chatName)
+		}
+		if modelName == "" {
+			modelName = preset.Model
+		}
+		tools = append([]string(nil), preset.Tools...)
+		system = preset.System
+	}
+
+	modelName, err := cfg.ResolveModel(modelName)
+	if err != nil {
+		if chatName == "" && cfg.DefaultModel == "" {
+			return fmt.Errorf("must specify --agent, --chat or --model, or set default_model in the configuration")
+		}
+		return err
+	}
+
+	// Create chat application
+	sess := session.New(session.KindChat, chatName, modelName)
+	sess.System = system
+	sess.Tools = tools
+	chatApp := agent.NewChatApp(modelName, tools, system, sess)
+
+	// Run chat interface
+	fmt.Printf("Starting chat session with Model %s...\n", modelName)
+	fmt.Printf("Session ID: %s\n", sess.ID)
+	if err := chatApp.Run(); err != nil {
+		return fmt.Errorf("failed to run chat interface: %w", err)
+	}
+	return nil
+}
+
+// parseTools splits a comma separated tool list
+func parseTools(toolsStr string) []string {
+	if toolsStr == "" {
+		return nil
+	}
+	tools := strings.Split(toolsStr, ",")
+	// Remove whitespace
+	for i, tool := range tools {
+		tools[i] = strings.TrimSpace(tool)
+	}
+	return tools
+}
+
 func init() {
 	// Add agent subcommand to root command
 	RootCmd.AddCommand(agentCmd)
@@ -102,6 +145,7 @@ func init() {
 	// Add parameters for agent subcommand
 	agentCmd.Flags().StringP("agent", "a", "", "Specify the Agent name to use")
 	agentCmd.Flags().StringP("chat", "c", "", "Specify chat preset name (from config file chats)")
-	agentCmd.Flags().StringP("model", "m", "", "Specify the Model to chat with (required when --chat is not specified)")
+	agentCmd.Flags().StringP("model", "m", "", "Specify the Model to chat with, or to run the Agent with (default is default_model)")
 	agentCmd.Flags().StringP("tools", "t", "", "Specify available tools, separated by commas (optional when --chat is not specified)")
+	agentCmd.Flags().StringP("resume", "r", "", "Resume a saved session by ID (see 'eino-cli sessions list')")
 }
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/tk103331/eino-cli/tools"
+)
+
+// newPromptApprover asks about tool calls on a terminal, one call at a time since the agent runs tools concurrently
+func newPromptApprover(in *bufio.Reader, out io.Writer) tools.Approver {
+	var mu sync.Mutex
+	return func(ctx context.Context, req tools.ApprovalRequest) tools.ApprovalDecision {
+		mu.Lock()
+		defer mu.Unlock()
+
+		fmt.Fprintf(out, "\n🔐 Allow tool %s to run?\n   📝 Arguments: %s\n", req.Tool, req.Arguments)
+		for {
+			fmt.Fprint(out, "   [y]es / [N]o / [e]dit arguments: ")
+			line, err := in.ReadString('\n')
+			if err != nil && (err != io.EOF || line == "") {
+				return tools.ApprovalDecision{Reason: "no answer on standard input"}
+			}
+			switch strings.ToLower(strings.TrimSpace(line)) {
+			case "y", "yes":
+				return tools.ApprovalDecision{Approved: true}
+			case "", "n", "no":
+				return tools.ApprovalDecision{Reason: "denied by user"}
+			case "e", "edit":
+				fmt.Fprint(out, "   Arguments (JSON): ")
+				line, err := in.ReadString('\n')
+				if err != nil && (err != io.EOF || line == "") {
+					return tools.ApprovalDecision{Reason: "no answer on standard input"}
+				}
+				if arguments := strings.TrimSpace(line); json.Valid([]byte(arguments)) {
+					return tools.ApprovalDecision{Approved: true, Arguments: arguments}
+				}
+				fmt.Fprintln(out, "   ❌ Arguments are not valid JSON")
+			}
+		}
+	}
+}
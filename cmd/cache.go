@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tk103331/eino-cli/config"
+	"github.com/tk103331/eino-cli/models"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the cache of model responses",
+	// The cache directory can be cleared without a configuration file
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached model responses",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var settings *config.CacheSettings
+		if cfg, err := config.LoadLayeredConfig(configPath); err == nil {
+			settings = cfg.Settings.Cache
+		}
+		dir := models.CacheDir(settings)
+		if err := models.ClearCache(settings); err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+		fmt.Printf("✅ Cache cleared: %s\n", dir)
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+	RootCmd.AddCommand(cacheCmd)
+}
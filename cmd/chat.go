@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/cloudwego/eino-ext/callbacks/langfuse"
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/spf13/cobra"
+	"github.com/tk103331/eino-cli/config"
+)
+
+var chatCmd = &cobra.Command{
+	Use:   "chat [preset]",
+	Short: "Start interactive chat session",
+	Long: `Start an interactive chat with a chat preset or a model.
+Without arguments, chats with default_model from the configuration.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetConfig()
+
+		if cfg.Settings.Langfuse != nil {
+			handler, flusher := langfuse.NewLangfuseHandler(cfg.Settings.Langfuse)
+			defer flusher()
+			callbacks.AppendGlobalHandlers(handler) // Set langfuse as global callback
+		}
+
+		// Get parameters
+		modelName, _ := cmd.Flags().GetString("model")
+		toolsStr, _ := cmd.Flags().GetString("tools")
+		chatName := ""
+		if len(args) > 0 {
+			chatName = args[0]
+		}
+
+		return startChat(chatName, modelName, parseTools(toolsStr))
+	},
+}
+
+func init() {
+	// Add chat subcommand to root command
+	RootCmd.AddCommand(chatCmd)
+
+	// Add parameters for chat subcommand
+	chatCmd.Flags().StringP("model", "m", "", "Specify the Model to chat with (default is default_model)")
+	chatCmd.Flags().StringP("tools", "t", "", "Specify available tools, separated by commas")
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tk103331/eino-cli/config"
+	"github.com/tk103331/eino-cli/mcp"
+	"github.com/tk103331/eino-cli/models"
+	"github.com/tk103331/eino-cli/tools"
+	"gopkg.in/yaml.v3"
+)
+
+var configCmd = &cobra.Command{
+	Use:               "config",
+	Short:             "Inspect and edit the configuration",
+	PersistentPreRunE: loadConfigOnly,
+}
+
+// loadConfigOnly loads the configuration without starting MCP servers or usage tracking
+func loadConfigOnly(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadLayeredConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration file: %w", err)
+	}
+	warnUntrustedProject(cfg)
+	return nil
+}
+
+// warnUntrustedProject tells the user that the project configuration file was skipped
+func warnUntrustedProject(cfg *config.Config) {
+	if path := cfg.UntrustedProject(); path != "" {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring untrusted project configuration %s, review it and run \"eino-cli config trust\" to load it\n", path)
+	}
+}
+
+// projectConfigArg returns the project configuration file given as argument or found from the current directory
+func projectConfigArg(args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+	if path := config.FindProjectConfig(); path != "" {
+		return path, nil
+	}
+	return "", fmt.Errorf("no %s found in the current directory or its parents", config.ProjectConfigFile)
+}
+
+var configTrustCmd = &cobra.Command{
+	Use:   "trust [file]",
+	Short: "Trust the project configuration file so that it is loaded",
+	Long: `Trust the project configuration file given, or the .eino-cli.yml found from the current directory upwards.
+A project configuration file can change provider endpoints, read local files and start commands, so it is
+only loaded once trusted. The trust covers the current content, the file must be trusted again after it changed.`,
+	Args: cobra.MaximumNArgs(1),
+	// Trusting must work even if the current configuration does not load
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		path, err := projectConfigArg(args)
+		if err != nil {
+			return err
+		}
+		if err := config.TrustProject(configPath, path); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Trusted %s\n", path)
+		return nil
+	},
+}
+
+var configUntrustCmd = &cobra.Command{
+	Use:               "untrust [file]",
+	Short:             "Stop loading a trusted project configuration file",
+	Args:              cobra.MaximumNArgs(1),
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		path, err := projectConfigArg(args)
+		if err != nil {
+			return err
+		}
+		if err := config.UntrustProject(configPath, path); err != nil {
+			return err
+		}
+		fmt.Printf("✅ No longer trusting %s\n", path)
+		return nil
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the merged configuration with secrets redacted",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetConfig()
+		node := cfg.MergedNode()
+		config.Redact(node)
+
+		fmt.Printf("# Merged from: %s\n", strings.Join(cfg.Files(), ", "))
+		return printYAML(node)
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <path>",
+	Short: "Print a value of the merged configuration, e.g. models.gpt4.temperature",
+	Long: `Print a value of the merged configuration, e.g. models.gpt4.temperature.
+Secrets are redacted as in "config show" unless --show-secrets is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		path, err := config.ParsePath(args[0])
+		if err != nil {
+			return err
+		}
+		merged := config.GetConfig().MergedNode()
+		if showSecrets, _ := cmd.Flags().GetBool("show-secrets"); !showSecrets {
+			config.Redact(merged)
+		}
+		node, err := config.LookupPath(merged, path)
+		if err != nil {
+			return err
+		}
+		if node.Kind == yaml.ScalarNode {
+			fmt.Println(node.Value)
+			return nil
+		}
+		return printYAML(node)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <path> <value>",
+	Short: "Set a value in the configuration file, keeping its comments",
+	Long: `Set a value in the user configuration file, or in the file given by --file.
+The value is parsed as YAML, so numbers, booleans and lists such as "[a, b]" keep their types.
+Missing keys are created.`,
+	Args: cobra.ExactArgs(2),
+	// Editing must work even if the current configuration does not load
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		file, _ := cmd.Flags().GetString("file")
+		if file == "" {
+			file = configPath
+		}
+		path, err := config.ParsePath(args[0])
+		if err != nil {
+			return err
+		}
+		if err := config.SetValue(file, path, args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Set %s in %s\n", args[0], file)
+		return nil
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration file for problems",
+	Long: `Check every reference between agents, chats, models, providers, tools and MCP servers,
+as well as the settings required by each tool, provider and MCP server type.
+Problems are reported as file:line:col.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		diags := validateConfig(config.GetConfig())
+		for _, d := range diags {
+			fmt.Println(d)
+		}
+		if len(diags) > 0 {
+			return fmt.Errorf("found %d problem(s) in configuration", len(diags))
+		}
+
+		fmt.Printf("✅ Configuration is valid: %s\n", strings.Join(config.GetConfig().Files(), ", "))
+		return nil
+	},
+}
+
+// validateConfig collects every problem in the configuration
+func validateConfig(cfg *config.Config) config.Diagnostics {
+	diags := config.Validate(cfg)
+
+	for name := range cfg.Providers {
+		models.ValidateProvider(cfg, name, &diags)
+	}
+	for name := range cfg.Models {
+		models.ValidateModel(cfg, name, &diags)
+	}
+	models.ValidateCache(cfg, &diags)
+	for name := range cfg.Tools {
+		tools.ValidateTool(cfg, name, &diags)
+	}
+	for name, server := range cfg.MCPServers {
+		if err := mcp.ValidateServerConfig(name, server); err != nil {
+			diags.Add(cfg, []string{"mcp_servers", name}, "%v", err)
+		}
+		if err := tools.ValidateApproval(server.Approval, server.ApprovalRules); err != nil {
+			diags.Add(cfg, []string{"mcp_servers", name, "approval"}, "%v", err)
+		}
+	}
+
+	diags.Sort()
+	return diags
+}
+
+// printYAML prints a YAML node to stdout
+func printYAML(node *yaml.Node) error {
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func init() {
+	configGetCmd.Flags().Bool("show-secrets", false, "Print secret values in plain text")
+	configSetCmd.Flags().String("file", "", "Configuration file to edit (default is the user configuration file)")
+
+	configCmd.AddCommand(configShowCmd, configGetCmd, configSetCmd, configValidateCmd, configTrustCmd, configUntrustCmd)
+	RootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tk103331/eino-cli/config"
+	"github.com/tk103331/eino-cli/models"
+	"gopkg.in/yaml.v3"
+)
+
+// providerDefaults holds the suggested settings for each provider type
+type providerDefaults struct {
+	BaseURL string
+	KeyEnv  string // Environment variable suggested for the API key
+	Model   string
+}
+
+var initDefaults = map[string]providerDefaults{
+	"openai":     {BaseURL: "https://api.openai.com/v1", KeyEnv: "OPENAI_API_KEY", Model: "gpt-4o"},
+	"claude":     {KeyEnv: "ANTHROPIC_API_KEY", Model: "claude-3-5-sonnet-20241022"},
+	"gemini":     {KeyEnv: "GEMINI_API_KEY", Model: "gemini-1.5-pro"},
+	"qwen":       {BaseURL: "https://dashscope.aliyuncs.com/compatible-mode/v1", KeyEnv: "DASHSCOPE_API_KEY", Model: "qwen-plus"},
+	"qianfan":    {KeyEnv: "QIANFAN_ACCESS_KEY", Model: "ERNIE-4.0-8K"},
+	"ark":        {BaseURL: "https://ark.cn-beijing.volces.com/api/v3", KeyEnv: "ARK_API_KEY"},
+	"deepseek":   {BaseURL: "https://api.deepseek.com", KeyEnv: "DEEPSEEK_API_KEY", Model: "deepseek-chat"},
+	"ollama":     {BaseURL: "http://localhost:11434", Model: "llama3"},
+	"azure":      {KeyEnv: "AZURE_OPENAI_API_KEY", Model: "gpt-4o"},
+	"openrouter": {BaseURL: "https://openrouter.ai/api/v1", KeyEnv: "OPENROUTER_API_KEY", Model: "openai/gpt-4o"},
+	"vllm":       {BaseURL: "http://localhost:8000/v1"},
+	"lmstudio":   {BaseURL: "http://localhost:1234/v1"},
+}
+
+// initOptions holds the answers used to generate the configuration file
+type initOptions struct {
+	ProviderType string
+	ProviderName string
+	BaseURL      string
+	APIKey       string
+	Model        string
+	ModelName    string
+}
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a configuration file",
+	Long: `Create a configuration file with a provider, a model and a default chat preset.
+Missing settings are asked interactively, unless --non-interactive is given or stdin is not a terminal.
+API keys are written as references such as ${OPENAI_API_KEY}, so the file can be shared safely.`,
+	// The configuration file does not exist yet
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		output, _ := cmd.Flags().GetString("output")
+		force, _ := cmd.Flags().GetBool("force")
+		nonInteractive, _ := cmd.Flags().GetBool("non-interactive")
+		if output == "" {
+			output = configPath
+		}
+		if _, err := os.Stat(output); err == nil && !force {
+			return fmt.Errorf("configuration file already exists: %s (use --force to overwrite)", output)
+		}
+
+		opts := initOptions{}
+		opts.ProviderType, _ = cmd.Flags().GetString("provider")
+		opts.ProviderName, _ = cmd.Flags().GetString("provider-name")
+		opts.BaseURL, _ = cmd.Flags().GetString("base-url")
+		opts.APIKey, _ = cmd.Flags().GetString("api-key")
+		opts.Model, _ = cmd.Flags().GetString("model")
+		opts.ModelName, _ = cmd.Flags().GetString("model-name")
+
+		if !nonInteractive && isTerminal(os.Stdin) {
+			if err := askInitOptions(&opts, bufio.NewReader(os.Stdin), os.Stdout, cmd.Flags().Changed); err != nil {
+				return err
+			}
+		}
+		if err := completeInitOptions(&opts); err != nil {
+			return err
+		}
+
+		data, err := renderInitConfig(opts)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+			return fmt.Errorf("failed to create configuration directory: %w", err)
+		}
+		if err := os.WriteFile(output, data, 0600); err != nil {
+			return fmt.Errorf("failed to write configuration file: %w", err)
+		}
+
+		fmt.Printf("✅ Configuration written to %s\n", output)
+		if env := initDefaults[opts.ProviderType].KeyEnv; env != "" && strings.Contains(opts.APIKey, env) {
+			fmt.Printf("Set the API key before running: export %s=...\n", env)
+		}
+		fmt.Println("Check it with: eino-cli config validate")
+		return nil
+	},
+}
+
+// askInitOptions prompts for every option that was not given as a flag
+func askInitOptions(opts *initOptions, in *bufio.Reader, out io.Writer, changed func(string) bool) error {
+	ask := func(flag, question, def string, target *string) error {
+		if changed(flag) {
+			return nil
+		}
+		if def != "" {
+			fmt.Fprintf(out, "%s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(out, "%s: ", question)
+		}
+		line, err := in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("failed to read answer: %w", err)
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			*target = line
+		} else {
+			*target = def
+		}
+		return nil
+	}
+
+	def := opts.ProviderType
+	if def == "" {
+		def = "openai"
+	}
+	if err := ask("provider", fmt.Sprintf("Provider type (%s)", strings.Join(models.ProviderTypes(), ", ")), def, &opts.ProviderType); err != nil {
+		return err
+	}
+	providerType, ok := models.Lookup(opts.ProviderType)
+	if !ok {
+		return fmt.Errorf("unsupported provider type: %s", opts.ProviderType)
+	}
+	opts.ProviderType = providerType.Name
+	defaults := initDefaults[opts.ProviderType]
+	if err := ask("base-url", "Base URL (leave empty for the provider default)", defaults.BaseURL, &opts.BaseURL); err != nil {
+		return err
+	}
+	keyRef := ""
+	if defaults.KeyEnv != "" {
+		keyRef = "${" + defaults.KeyEnv + "}"
+	}
+	if err := ask("api-key", "API key or reference (${ENV_VAR}, ${file:/path})", keyRef, &opts.APIKey); err != nil {
+		return err
+	}
+	if err := ask("model", "Default model", defaults.Model, &opts.Model); err != nil {
+		return err
+	}
+	return nil
+}
+
+// completeInitOptions fills in defaults for options that were not given and checks the result
+func completeInitOptions(opts *initOptions) error {
+	if opts.ProviderType == "" {
+		return fmt.Errorf("must specify --provider, one of: %s", strings.Join(models.ProviderTypes(), ", "))
+	}
+	providerType, ok := models.Lookup(opts.ProviderType)
+	if !ok {
+		return fmt.Errorf("unsupported provider type: %s, must be one of: %s", opts.ProviderType, strings.Join(models.ProviderTypes(), ", "))
+	}
+	opts.ProviderType = providerType.Name
+	// Provider types registered by embedding programs may have no defaults
+	defaults := initDefaults[opts.ProviderType]
+	if opts.ProviderName == "" {
+		opts.ProviderName = opts.ProviderType
+	}
+	if opts.BaseURL == "" {
+		opts.BaseURL = defaults.BaseURL
+	}
+	if opts.APIKey == "" && defaults.KeyEnv != "" {
+		opts.APIKey = "${" + defaults.KeyEnv + "}"
+	}
+	if opts.Model == "" {
+		opts.Model = defaults.Model
+	}
+	if opts.Model == "" {
+		return fmt.Errorf("must specify --model for provider type %s", opts.ProviderType)
+	}
+	if opts.ModelName == "" {
+		opts.ModelName = opts.Model
+	}
+	return nil
+}
+
+// renderInitConfig generates the configuration file content
+func renderInitConfig(opts initOptions) ([]byte, error) {
+	provider := config.Provider{
+		Type:    opts.ProviderType,
+		BaseURL: opts.BaseURL,
+		APIKey:  opts.APIKey,
+	}
+	if opts.ProviderType == "qianfan" {
+		// Qianfan signs requests with an access key and a secret key
+		provider.APIKey = ""
+		provider.AccessKey = opts.APIKey
+		provider.SecretKey = "${QIANFAN_SECRET_KEY}"
+	}
+
+	cfg := config.Config{
+		Providers: map[string]config.Provider{
+			opts.ProviderName: provider,
+		},
+		Models: map[string]config.Model{
+			opts.ModelName: {
+				Provider: opts.ProviderName,
+				Model:    opts.Model,
+			},
+		},
+		DefaultModel: opts.ModelName,
+		Chats: map[string]config.Chat{
+			"default": {
+				Model:  opts.ModelName,
+				System: "You are a helpful assistant.",
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Eino CLI configuration generated by `eino-cli init`.\n")
+	buf.WriteString("# See config.yml.example for agents, tools and MCP servers.\n")
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// isTerminal reports whether the file is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func init() {
+	RootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().String("provider", "", "Provider type, e.g. openai, claude, ollama")
+	initCmd.Flags().String("provider-name", "", "Provider name in the configuration (default is the provider type)")
+	initCmd.Flags().String("base-url", "", "Provider base URL (default depends on the provider type)")
+	initCmd.Flags().String("api-key", "", "API key or reference such as ${OPENAI_API_KEY} (default is the provider's usual environment variable)")
+	initCmd.Flags().String("model", "", "Model used by default, e.g. gpt-4o")
+	initCmd.Flags().String("model-name", "", "Model name in the configuration (default is the model)")
+	initCmd.Flags().StringP("output", "o", "", "File to write (default is the user configuration file)")
+	initCmd.Flags().Bool("force", false, "Overwrite an existing configuration file")
+	initCmd.Flags().Bool("non-interactive", false, "Do not prompt, use flags and defaults only")
+}
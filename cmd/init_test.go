@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tk103331/eino-cli/config"
+)
+
+func TestCompleteInitOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    initOptions
+		want    initOptions
+		wantErr string
+	}{
+		{
+			name: "provider defaults",
+			opts: initOptions{ProviderType: "OpenAI"},
+			want: initOptions{ProviderType: "openai", ProviderName: "openai", BaseURL: "https://api.openai.com/v1", APIKey: "${OPENAI_API_KEY}", Model: "gpt-4o", ModelName: "gpt-4o"},
+		},
+		{
+			name: "flags win over defaults",
+			opts: initOptions{ProviderType: "claude", ProviderName: "anthropic", APIKey: "${file:~/.claude-key}", Model: "claude-x", ModelName: "main"},
+			want: initOptions{ProviderType: "claude", ProviderName: "anthropic", APIKey: "${file:~/.claude-key}", Model: "claude-x", ModelName: "main"},
+		},
+		{
+			name: "no key",
+			opts: initOptions{ProviderType: "ollama"},
+			want: initOptions{ProviderType: "ollama", ProviderName: "ollama", BaseURL: "http://localhost:11434", Model: "llama3", ModelName: "llama3"},
+		},
+		{name: "no model default", opts: initOptions{ProviderType: "vllm"}, wantErr: "must specify --model"},
+		{name: "unsupported", opts: initOptions{ProviderType: "nope"}, wantErr: "unsupported provider type"},
+		{name: "missing", wantErr: "must specify --provider"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			err := completeInitOptions(&opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if opts != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, opts)
+			}
+		})
+	}
+}
+
+func TestAskInitOptions(t *testing.T) {
+	// Empty answers take the suggested default, the model flag is not asked
+	in := bufio.NewReader(strings.NewReader("deepseek\n\n${DS_KEY}\n"))
+	opts := initOptions{Model: "deepseek-reasoner"}
+	changed := func(flag string) bool { return flag == "model" }
+	if err := askInitOptions(&opts, in, io.Discard, changed); err != nil {
+		t.Fatal(err)
+	}
+	want := initOptions{ProviderType: "deepseek", BaseURL: "https://api.deepseek.com", APIKey: "${DS_KEY}", Model: "deepseek-reasoner"}
+	if opts != want {
+		t.Errorf("expected %+v, got %+v", want, opts)
+	}
+
+	opts = initOptions{}
+	if err := askInitOptions(&opts, bufio.NewReader(strings.NewReader("nope\n")), io.Discard, func(string) bool { return false }); err == nil {
+		t.Error("expected an unsupported provider type to fail")
+	}
+}
+
+func TestRenderInitConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         initOptions
+		wantProvider config.Provider
+	}{
+		{
+			name:         "api key",
+			opts:         initOptions{ProviderType: "openai"},
+			wantProvider: config.Provider{Type: "openai", BaseURL: "https://api.openai.com/v1", APIKey: "sk-test"},
+		},
+		{
+			name:         "access and secret key",
+			opts:         initOptions{ProviderType: "qianfan"},
+			wantProvider: config.Provider{Type: "qianfan", AccessKey: "ak-test", SecretKey: "sk-secret"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OPENAI_API_KEY", "sk-test")
+			t.Setenv("QIANFAN_ACCESS_KEY", "ak-test")
+			t.Setenv("QIANFAN_SECRET_KEY", "sk-secret")
+			opts := tt.opts
+			if err := completeInitOptions(&opts); err != nil {
+				t.Fatal(err)
+			}
+			data, err := renderInitConfig(opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(data), "sk-test") || strings.Contains(string(data), "sk-secret") {
+				t.Errorf("expected keys to be written as references:\n%s", data)
+			}
+
+			path := filepath.Join(t.TempDir(), "config.yml")
+			if err := os.WriteFile(path, data, 0600); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := config.LoadConfig(path)
+			if err != nil {
+				t.Fatalf("expected the generated file to load: %v\n%s", err, data)
+			}
+			if diags := config.Validate(cfg); len(diags) != 0 {
+				t.Errorf("expected the generated file to be valid, got %v", diags)
+			}
+			provider := cfg.Providers[opts.ProviderName]
+			if provider.Type != tt.wantProvider.Type || provider.BaseURL != tt.wantProvider.BaseURL || provider.APIKey != tt.wantProvider.APIKey ||
+				provider.AccessKey != tt.wantProvider.AccessKey || provider.SecretKey != tt.wantProvider.SecretKey {
+				t.Errorf("expected provider %+v, got %+v", tt.wantProvider, provider)
+			}
+			if cfg.DefaultModel != opts.ModelName || cfg.Chats["default"].Model != opts.ModelName {
+				t.Errorf("expected default_model and the default chat to use %s", opts.ModelName)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tk103331/eino-cli/config"
+	"github.com/tk103331/eino-cli/models"
+	"github.com/tk103331/eino-cli/tools"
+)
+
+var listCmd = &cobra.Command{
+	Use:               "list",
+	Short:             "List configured agents, models, tools, MCP servers and chats",
+	PersistentPreRunE: loadConfigOnly,
+}
+
+var listAgentsCmd = &cobra.Command{
+	Use:   "agents",
+	Short: "List configured agents",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetConfig()
+		return printTable([]string{"NAME", "MODEL", "TOOLS", "MCP SERVERS"}, sortedNames(cfg.Agents), func(name string) []string {
+			a := cfg.Agents[name]
+			return []string{name, a.Model, strings.Join(a.Tools, ","), strings.Join(a.MCPServers, ",")}
+		})
+	},
+}
+
+var listModelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "List configured models",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetConfig()
+		return printTable([]string{"NAME", "PROVIDER", "MODEL", "DEFAULT"}, sortedNames(cfg.Models), func(name string) []string {
+			m := cfg.Models[name]
+			def := ""
+			if name == cfg.DefaultModel {
+				def = "*"
+			}
+			return []string{name, m.Provider, m.Model, def}
+		})
+	},
+}
+
+var listToolsCmd = &cobra.Command{
+	Use:   "tools",
+	Short: "List configured tools",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetConfig()
+		return printTable([]string{"NAME", "TYPE", "DESCRIPTION"}, sortedNames(cfg.Tools), func(name string) []string {
+			t := cfg.Tools[name]
+			return []string{name, t.Type, t.Description}
+		})
+	},
+}
+
+var listMCPServersCmd = &cobra.Command{
+	Use:   "mcp-servers",
+	Short: "List configured MCP servers",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetConfig()
+		return printTable([]string{"NAME", "TYPE", "TARGET"}, sortedNames(cfg.MCPServers), func(name string) []string {
+			s := cfg.MCPServers[name]
+			target := s.URL
+			if s.Cmd != "" {
+				target = strings.TrimSpace(s.Cmd + " " + strings.Join(s.Args, " "))
+			}
+			return []string{name, s.Type, target}
+		})
+	},
+}
+
+var listChatsCmd = &cobra.Command{
+	Use:   "chats",
+	Short: "List configured chat presets",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetConfig()
+		return printTable([]string{"NAME", "MODEL", "TOOLS"}, sortedNames(cfg.Chats), func(name string) []string {
+			c := cfg.Chats[name]
+			return []string{name, c.Model, strings.Join(c.Tools, ",")}
+		})
+	},
+}
+
+var listProvidersCmd = &cobra.Command{
+	Use:   "providers",
+	Short: "List supported provider types, their required settings and the providers configured for them",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetConfig()
+		configured := make(map[string][]string)
+		for _, name := range sortedNames(cfg.Providers) {
+			typ := strings.ToLower(cfg.Providers[name].Type)
+			configured[typ] = append(configured[typ], name)
+		}
+		return printTable([]string{"TYPE", "REQUIRED", "CONFIGURED"}, models.ProviderTypes(), func(typ string) []string {
+			t, _ := models.Lookup(typ)
+			return []string{typ, strings.Join(t.Required, ","), strings.Join(configured[typ], ",")}
+		})
+	},
+}
+
+var listToolTypesCmd = &cobra.Command{
+	Use:   "tool-types",
+	Short: "List supported tool types and their required config attributes",
+	// Tool types do not depend on the configuration
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+	RunE: func(cmd *cobra.Command, args []string) error {
+		types := tools.Types()
+		names := make([]string, len(types))
+		for i, t := range types {
+			names[i] = t.Name
+		}
+		return printTable([]string{"TYPE", "REQUIRED CONFIG"}, names, func(name string) []string {
+			t, _ := tools.Lookup(name)
+			return []string{name, strings.Join(t.Required, ",")}
+		})
+	},
+}
+
+var listAPIKeysCmd = &cobra.Command{
+	Use:   "api-keys",
+	Short: "List usage of provider API keys",
+	Long:  "List calls, errors, token usage and cool-down of each provider API key, accumulated in " + models.KeyUsagePath(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stats, err := models.LoadKeyUsage()
+		if err != nil {
+			return err
+		}
+		if len(stats) == 0 {
+			fmt.Println("No API key usage recorded")
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PROVIDER\tKEY\tID\tCALLS\tERRORS\tPROMPT TOKENS\tCOMPLETION TOKENS\tLAST USED\tCOOLING DOWN UNTIL")
+		for _, s := range stats {
+			cooldown := ""
+			if s.CooldownUntil.After(time.Now()) {
+				cooldown = s.CooldownUntil.Format(time.DateTime)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%d\t%s\t%s\n", s.Provider, s.Key, s.ID, s.Calls, s.Errors,
+				s.PromptTokens, s.CompletionTokens, s.LastUsed.Format(time.DateTime), cooldown)
+		}
+		return w.Flush()
+	},
+}
+
+// printTable prints one row per name as an aligned table
+func printTable(header []string, names []string, row func(name string) []string) error {
+	if len(names) == 0 {
+		fmt.Println("Nothing configured")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, name := range names {
+		fmt.Fprintln(w, strings.Join(row(name), "\t"))
+	}
+	return w.Flush()
+}
+
+// sortedNames returns the keys of a configuration section in alphabetical order
+func sortedNames[T any](m map[string]T) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	listCmd.AddCommand(listAgentsCmd, listModelsCmd, listToolsCmd, listMCPServersCmd, listChatsCmd, listProvidersCmd, listToolTypesCmd, listAPIKeysCmd)
+	RootCmd.AddCommand(listCmd)
+}
@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/spf13/cobra"
+	"github.com/tk103331/eino-cli/config"
+	"github.com/tk103331/eino-cli/models"
+)
+
+const defaultBenchPrompt = "Write a short paragraph about the history of the Go programming language."
+
+var modelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "List, probe and benchmark configured models",
+	// Probing models does not need MCP servers
+	PersistentPreRunE: loadConfigOnly,
+}
+
+var modelsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured models with their providers",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetConfig()
+		return printTable([]string{"NAME", "PROVIDER", "TYPE", "MODEL", "DEFAULT"}, sortedNames(cfg.Models), func(name string) []string {
+			m := cfg.Models[name]
+			def := ""
+			if name == cfg.DefaultModel {
+				def = "*"
+			}
+			return []string{name, m.Provider, cfg.Providers[m.Provider].Type, m.Model, def}
+		})
+	},
+}
+
+var modelsPingCmd = &cobra.Command{
+	Use:   "ping [model]",
+	Short: "Send a minimal request to a model and report latency",
+	Long:  "Send a minimal request to a model and report latency and errors. Without a model name, default_model is used.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		modelName := ""
+		if len(args) > 0 {
+			modelName = args[0]
+		}
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		cfg := config.GetConfig()
+		modelName, err := cfg.ResolveModel(modelName)
+		if err != nil {
+			return err
+		}
+		chatModel, err := models.NewFactory(cfg).CreateProviderModel(ctx, modelName)
+		if err != nil {
+			return fmt.Errorf("failed to create model %s: %w", modelName, err)
+		}
+
+		start := time.Now()
+		msg, err := chatModel.Generate(ctx, []*schema.Message{schema.UserMessage("Reply with the single word: pong")})
+		latency := time.Since(start)
+		if err != nil {
+			return fmt.Errorf("❌ %s failed after %v: %w", modelName, latency.Round(time.Millisecond), err)
+		}
+		fmt.Printf("✅ %s responded in %v: %s\n", modelName, latency.Round(time.Millisecond), strings.TrimSpace(msg.Content))
+		return nil
+	},
+}
+
+// benchResult accumulates the measurements of one model
+type benchResult struct {
+	model      string
+	runs       int
+	errors     int
+	firstToken time.Duration // Sum over successful runs
+	total      time.Duration // Sum over successful runs
+	tokens     int           // Completion tokens reported by the provider
+	tokenTime  time.Duration // Generation time of the runs that reported tokens
+	lastErr    error
+}
+
+var modelsBenchCmd = &cobra.Command{
+	Use:   "bench [model...]",
+	Short: "Run a prompt set against models concurrently and compare speed",
+	Long: `Run a prompt set against several models concurrently, streaming every response.
+Reports the average time to first token, the total time, tokens per second and errors.
+Without model names, every configured model is benchmarked.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		cfg := config.GetConfig()
+		names := args
+		if len(names) == 0 {
+			names = sortedNames(cfg.Models)
+		}
+		for _, name := range names {
+			if _, ok := cfg.Models[name]; !ok {
+				return fmt.Errorf("model configuration does not exist: %s", name)
+			}
+		}
+		prompts, err := benchPrompts(cmd)
+		if err != nil {
+			return err
+		}
+		repeat, _ := cmd.Flags().GetInt("repeat")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		fmt.Printf("Benchmarking %d model(s) with %d prompt(s) x %d...\n", len(names), len(prompts), repeat)
+		results := make([]*benchResult, len(names))
+		var wg sync.WaitGroup
+		for i, name := range names {
+			wg.Add(1)
+			go func(i int, name string) {
+				defer wg.Done()
+				results[i] = benchModel(cfg, name, prompts, repeat, timeout)
+			}(i, name)
+		}
+		wg.Wait()
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "MODEL\tRUNS\tERRORS\tAVG FIRST TOKEN\tAVG TOTAL\tTOKENS/S")
+		for _, r := range results {
+			firstToken, total, speed := "-", "-", "-"
+			if ok := r.runs - r.errors; ok > 0 {
+				firstToken = (r.firstToken / time.Duration(ok)).Round(time.Millisecond).String()
+				total = (r.total / time.Duration(ok)).Round(time.Millisecond).String()
+			}
+			if r.tokens > 0 && r.tokenTime > 0 {
+				speed = fmt.Sprintf("%.1f", float64(r.tokens)/r.tokenTime.Seconds())
+			}
+			fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\n", r.model, r.runs, r.errors, firstToken, total, speed)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		for _, r := range results {
+			if r.lastErr != nil {
+				fmt.Printf("❌ %s: %v\n", r.model, r.lastErr)
+			}
+		}
+		return nil
+	},
+}
+
+// benchPrompts reads the prompts from --prompt and --prompts-file, one prompt per line in the file
+func benchPrompts(cmd *cobra.Command) ([]string, error) {
+	prompts, _ := cmd.Flags().GetStringArray("prompt")
+	if file, _ := cmd.Flags().GetString("prompts-file"); file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prompts: %w", err)
+		}
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				prompts = append(prompts, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read prompts: %w", err)
+		}
+	}
+	if len(prompts) == 0 {
+		prompts = []string{defaultBenchPrompt}
+	}
+	return prompts, nil
+}
+
+// benchModel runs every prompt against one model, one after another
+func benchModel(cfg *config.Config, name string, prompts []string, repeat int, timeout time.Duration) *benchResult {
+	result := &benchResult{model: name}
+	chatModel, err := models.NewFactory(cfg).CreateProviderModel(context.Background(), name)
+	if err != nil {
+		result.lastErr = err
+		return result
+	}
+
+	for i := 0; i < repeat; i++ {
+		for _, prompt := range prompts {
+			result.runs++
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			firstToken, total, tokens, err := benchRun(ctx, chatModel, prompt)
+			cancel()
+			if err != nil {
+				result.errors++
+				result.lastErr = err
+				continue
+			}
+			result.firstToken += firstToken
+			result.total += total
+			if tokens > 0 {
+				result.tokens += tokens
+				result.tokenTime += total - firstToken
+			}
+		}
+	}
+	return result
+}
+
+// benchRun streams one response and measures it; tokens is 0 if the provider does not report usage
+func benchRun(ctx context.Context, chatModel model.BaseChatModel, prompt string) (firstToken, total time.Duration, tokens int, err error) {
+	start := time.Now()
+	stream, err := chatModel.Stream(ctx, []*schema.Message{schema.UserMessage(prompt)})
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer stream.Close()
+
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		if firstToken == 0 && (chunk.Content != "" || chunk.ReasoningContent != "") {
+			firstToken = time.Since(start)
+		}
+		if chunk.ResponseMeta != nil && chunk.ResponseMeta.Usage != nil && chunk.ResponseMeta.Usage.CompletionTokens > 0 {
+			tokens = chunk.ResponseMeta.Usage.CompletionTokens
+		}
+	}
+	total = time.Since(start)
+	if firstToken == 0 {
+		firstToken = total
+	}
+	return firstToken, total, tokens, nil
+}
+
+func init() {
+	modelsPingCmd.Flags().Duration("timeout", 30*time.Second, "Request timeout")
+	modelsBenchCmd.Flags().StringArrayP("prompt", "p", nil, "Prompt to run, can be repeated")
+	modelsBenchCmd.Flags().String("prompts-file", "", "File with one prompt per line")
+	modelsBenchCmd.Flags().Int("repeat", 1, "Number of times each prompt is run")
+	modelsBenchCmd.Flags().Duration("timeout", 2*time.Minute, "Timeout of each request")
+
+	modelsCmd.AddCommand(modelsListCmd, modelsPingCmd, modelsBenchCmd)
+	RootCmd.AddCommand(modelsCmd)
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+// buildPrompt assembles the user prompt from --prompt, --prompt-file, --var and --attach flags
+func buildPrompt(cmd *cobra.Command) (string, error) {
+	prompt, _ := cmd.Flags().GetString("prompt")
+	promptFile, _ := cmd.Flags().GetString("prompt-file")
+	vars, _ := cmd.Flags().GetStringArray("var")
+	attachments, _ := cmd.Flags().GetStringArray("attach")
+
+	if prompt != "" && promptFile != "" {
+		return "", fmt.Errorf("--prompt and --prompt-file cannot be used together")
+	}
+	if prompt == "" && promptFile == "" {
+		return "", fmt.Errorf("must specify --prompt or --prompt-file")
+	}
+
+	// Read prompt text, "-" means standard input
+	stdinUsed := false
+	var err error
+	switch {
+	case prompt == "-":
+		prompt, err = readStdin()
+		stdinUsed = true
+	case promptFile == "-":
+		prompt, err = readStdin()
+		stdinUsed = true
+	case promptFile != "":
+		var data []byte
+		data, err = os.ReadFile(promptFile)
+		prompt = string(data)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read prompt: %w", err)
+	}
+
+	// Render prompt as template when variables are provided
+	if len(vars) > 0 {
+		prompt, err = renderPromptTemplate(prompt, vars)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Append attached files to the user message
+	var sb strings.Builder
+	sb.WriteString(strings.TrimRight(prompt, "\n"))
+	for _, path := range attachments {
+		var content string
+		if path == "-" {
+			if stdinUsed {
+				return "", fmt.Errorf("standard input can only be used once")
+			}
+			stdinUsed = true
+			content, err = readStdin()
+			path = "stdin"
+		} else {
+			var data []byte
+			data, err = os.ReadFile(path)
+			content = string(data)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read attachment %s: %w", path, err)
+		}
+		sb.WriteString(formatAttachment(path, content))
+	}
+
+	if strings.TrimSpace(sb.String()) == "" {
+		return "", fmt.Errorf("prompt is empty")
+	}
+	return sb.String(), nil
+}
+
+// renderPromptTemplate renders the prompt with text/template using key=value variables
+func renderPromptTemplate(prompt string, vars []string) (string, error) {
+	data := make(map[string]interface{}, len(vars))
+	for _, v := range vars {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return "", fmt.Errorf("invalid --var %q, must be key=value", v)
+		}
+		data[strings.TrimSpace(key)] = value
+	}
+
+	tmpl, err := template.New("prompt").Option("missingkey=error").Parse(prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse prompt template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// formatAttachment formats file content as a fenced block appended to the prompt
+func formatAttachment(path, content string) string {
+	lang := strings.TrimPrefix(filepath.Ext(path), ".")
+	return fmt.Sprintf("\n\nFile: %s\n```%s\n%s\n```", path, lang, strings.TrimRight(content, "\n"))
+}
+
+// readStdin reads all of standard input
+func readStdin() (string, error) {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
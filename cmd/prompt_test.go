@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestBuildPrompt(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	promptFile := writeTestFile("prompt.txt", "Review {{.file}} for {{.focus}}\n")
+	attachment := writeTestFile("main.go", "package main\n")
+
+	tests := []struct {
+		name    string
+		args    []string
+		stdin   string
+		want    string
+		wantErr string
+	}{
+		{name: "flag", args: []string{"--prompt", "hello"}, want: "hello"},
+		{name: "stdin", args: []string{"--prompt", "-"}, stdin: "from stdin\n", want: "from stdin"},
+		{name: "file from stdin", args: []string{"--prompt-file", "-"}, stdin: "piped", want: "piped"},
+		{
+			name: "file with vars",
+			args: []string{"--prompt-file", promptFile, "--var", "file=main.go", "--var", "focus=a=b"},
+			want: "Review main.go for a=b",
+		},
+		{
+			name:  "attachments",
+			args:  []string{"-p", "explain", "--attach", attachment, "--attach", "-"},
+			stdin: "log line\n",
+			want:  "explain\n\nFile: " + attachment + "\n```go\npackage main\n```\n\nFile: stdin\n```\nlog line\n```",
+		},
+		{name: "missing var", args: []string{"--prompt-file", promptFile, "--var", "file=x"}, wantErr: "focus"},
+		{name: "invalid var", args: []string{"-p", "x", "--var", "novalue"}, wantErr: "must be key=value"},
+		{name: "both", args: []string{"-p", "x", "--prompt-file", promptFile}, wantErr: "cannot be used together"},
+		{name: "none", wantErr: "must specify"},
+		{name: "stdin twice", args: []string{"-p", "-", "--attach", "-"}, stdin: "x", wantErr: "only be used once"},
+		{name: "empty", args: []string{"-p", "-"}, stdin: " \n", wantErr: "prompt is empty"},
+		{name: "missing file", args: []string{"--prompt-file", filepath.Join(dir, "missing.txt")}, wantErr: "failed to read prompt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().StringP("prompt", "p", "", "")
+			cmd.Flags().String("prompt-file", "", "")
+			cmd.Flags().StringArray("attach", nil, "")
+			cmd.Flags().StringArray("var", nil, "")
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			stdin := writeTestFile("stdin", tt.stdin)
+			f, err := os.Open(stdin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			oldStdin := os.Stdin
+			os.Stdin = f
+			defer func() { os.Stdin = oldStdin }()
+
+			got, err := buildPrompt(cmd)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("expected prompt %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -9,10 +9,14 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/tk103331/eino-cli/config"
 	"github.com/tk103331/eino-cli/mcp"
+	"github.com/tk103331/eino-cli/models"
+	"github.com/tk103331/eino-cli/usage"
 )
 
 var (
 	configPath string
+	recordPath string
+	noCache    bool
 )
 
 // RootCmd represents the base command when called without any subcommands
@@ -22,10 +26,25 @@ var RootCmd = &cobra.Command{
 	Long:  `A command line interface for Eino`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// Load configuration file
-		cfg, err := config.LoadConfig(configPath)
+		cfg, err := config.LoadLayeredConfig(configPath)
 		if err != nil {
 			return fmt.Errorf("failed to load configuration file: %w", err)
 		}
+		warnUntrustedProject(cfg)
+
+		// Track token usage of every model call
+		usage.InitializeGlobalTracker(cfg)
+
+		if noCache {
+			models.DisableCache()
+		}
+
+		// Record model exchanges for replay by the mock provider
+		if recordPath != "" {
+			if err := models.RecordTo(recordPath); err != nil {
+				return err
+			}
+		}
 
 		// Asynchronously initialize MCP manager (does not block command execution)
 		go func() {
@@ -46,12 +65,19 @@ var RootCmd = &cobra.Command{
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	if err := RootCmd.Execute(); err != nil {
-		fmt.Println(err)
+	err := RootCmd.Execute()
+	Shutdown()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
 
+// Shutdown saves state kept in memory, such as API key usage, before the program exits
+func Shutdown() {
+	models.FlushKeyUsage()
+}
+
 func init() {
 	// Get user home directory
 	homeDir, err := os.UserHomeDir()
@@ -61,5 +87,7 @@ func init() {
 	defaultConfigPath := filepath.Join(homeDir, ".eino-cli", "config.yml")
 
 	// Add global parameters
-	RootCmd.PersistentFlags().StringVar(&configPath, "config", defaultConfigPath, "Configuration file path")
+	RootCmd.PersistentFlags().StringVar(&configPath, "config", defaultConfigPath, "User configuration file path")
+	RootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Do not use cached model responses")
+	RootCmd.PersistentFlags().StringVar(&recordPath, "record", "", "Record model exchanges into a cassette file (.json or .yml) for the mock provider")
 }
@@ -1,15 +1,21 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/cloudwego/eino-ext/callbacks/langfuse"
 	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/schema"
 	"github.com/spf13/cobra"
 	"github.com/tk103331/eino-cli/agent"
 	"github.com/tk103331/eino-cli/config"
+	"github.com/tk103331/eino-cli/tools"
+	"github.com/tk103331/eino-cli/usage"
 )
 
 // printHeader prints a formatted header for better visual separation
@@ -24,6 +30,15 @@ func printSuccess(message string, start time.Time) {
 	fmt.Printf("\n✅ %s (completed in %v)\n", message, duration.Round(time.Millisecond))
 }
 
+// truncatePrompt shortens long prompts for display
+func truncatePrompt(prompt string) string {
+	prompt = strings.TrimSpace(prompt)
+	if len(prompt) > 200 {
+		return prompt[:197] + "..."
+	}
+	return prompt
+}
+
 // printError prints an error message with better formatting
 func printError(message string, err error) {
 	fmt.Printf("\n❌ %s: %v\n", message, err)
@@ -40,11 +55,38 @@ var runCmd = &cobra.Command{
 
 		// Get parameters
 		agentName, _ := cmd.Flags().GetString("agent")
-		prompt, _ := cmd.Flags().GetString("prompt")
+		modelName, _ := cmd.Flags().GetString("model")
+		output, _ := cmd.Flags().GetString("output")
+
+		// Assemble prompt from flags, files and standard input
+		prompt, err := buildPrompt(cmd)
+		if err != nil {
+			return err
+		}
+
+		// Tool calls that need approval are asked on the terminal, and denied when stdin is not one
+		if isTerminal(os.Stdin) {
+			tools.SetApprover(newPromptApprover(bufio.NewReader(os.Stdin), os.Stderr))
+		}
+
+		switch output {
+		case outputText:
+		case outputJSON, outputNDJSON:
+			// Errors are reported in the structured output, keep stdout machine-readable
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+			return runStructured(cmd.Context(), cfg, agentName, modelName, prompt, output)
+		default:
+			return fmt.Errorf("unsupported output format: %s (must be text, json or ndjson)", output)
+		}
 
 		// Print execution header
 		printHeader("Agent Execution")
-		fmt.Printf("🤖 Agent: %s\n📝 Prompt: %s\n", agentName, prompt)
+		fmt.Printf("🤖 Agent: %s\n", agentName)
+		if model := resolveAgentModel(cfg, agentName, modelName); model != "" {
+			fmt.Printf("🧠 Model: %s\n", model)
+		}
+		fmt.Printf("📝 Prompt: %s\n", truncatePrompt(prompt))
 
 		// Initialize phase
 		fmt.Printf("\n⚙️  Initializing...")
@@ -61,7 +103,7 @@ var runCmd = &cobra.Command{
 		factory := agent.NewFactory(cfg)
 
 		// Create Agent
-		agentInstance, err := factory.CreateAgent(agentName)
+		agentInstance, err := factory.CreateAgentWithModel(agentName, modelName)
 		if err != nil {
 			printError("Failed to create agent", err)
 			return fmt.Errorf("failed to create Agent: %w", err)
@@ -81,21 +123,84 @@ var runCmd = &cobra.Command{
 		printSuccess("Agent execution completed", execStart)
 		printHeader("Summary")
 		fmt.Printf("⏱️  Total execution time: %v\n", time.Since(startTime).Round(time.Millisecond))
+		if tracker := usage.GetGlobalTracker(); tracker != nil {
+			fmt.Printf("🪙 Token usage: %s\n", tracker.Session())
+			if byModel := tracker.ByModel(); len(byModel) > 1 {
+				for _, name := range tracker.ModelNames() {
+					fmt.Printf("   • %s: %s\n", name, byModel[name])
+				}
+			}
+		}
 		fmt.Println()
 
 		return nil
 	},
 }
 
+// runStructured runs the agent and reports the result as JSON or an NDJSON event stream
+func runStructured(ctx context.Context, cfg *config.Config, agentName, modelName, prompt, format string) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	recorder := newRunRecorder(os.Stdout, format, agentName, resolveAgentModel(cfg, agentName, modelName), prompt)
+	recorder.begin()
+
+	if cfg.Settings.Langfuse != nil {
+		handler, flusher := langfuse.NewLangfuseHandler(cfg.Settings.Langfuse)
+		defer flusher()
+		callbacks.AppendGlobalHandlers(handler) // Set langfuse as global callback
+	}
+
+	// Create Agent
+	agentInstance, err := agent.NewFactory(cfg).CreateAgentWithModel(agentName, modelName)
+	if err != nil {
+		err = fmt.Errorf("failed to create Agent: %w", err)
+		recorder.finish(nil, err)
+		return err
+	}
+	recorder.initialized()
+
+	// Run Agent
+	turn, err := agentInstance.ChatStreamWithHistory(ctx, []*schema.Message{schema.UserMessage(prompt)},
+		recorder.chunkCallback, recorder.toolCallback)
+	var reply *schema.Message
+	if err != nil {
+		err = fmt.Errorf("failed to run Agent: %w", err)
+	} else if len(turn) > 0 {
+		reply = turn[len(turn)-1]
+	}
+	if outErr := recorder.finish(reply, err); outErr != nil && err == nil {
+		err = fmt.Errorf("failed to write output: %w", outErr)
+	}
+	return err
+}
+
+// resolveAgentModel returns the model an agent runs with, or an empty string if it cannot be resolved
+func resolveAgentModel(cfg *config.Config, agentName, modelName string) string {
+	if modelName == "" {
+		modelName = cfg.Agents[agentName].Model
+	}
+	resolved, err := cfg.ResolveModel(modelName)
+	if err != nil {
+		return ""
+	}
+	return resolved
+}
+
 func init() {
 	// Add run subcommand to root command
 	RootCmd.AddCommand(runCmd)
 
 	// Add parameters for run subcommand
 	runCmd.Flags().StringP("agent", "a", "", "Specify the Agent to run")
-	runCmd.Flags().StringP("prompt", "p", "", "Specify the prompt for Agent")
+	runCmd.Flags().StringP("model", "m", "", "Run the Agent with this Model instead of its configured one")
+	runCmd.Flags().StringP("prompt", "p", "", "Specify the prompt for Agent, use - to read from stdin")
+	runCmd.Flags().String("prompt-file", "", "Read the prompt from a file, use - to read from stdin")
+	runCmd.Flags().StringArray("attach", nil, "Attach file contents to the prompt (can be repeated)")
+	runCmd.Flags().StringArray("var", nil, "Set a prompt template variable as key=value (can be repeated)")
+	runCmd.Flags().StringP("output", "o", outputText, "Output format: text, json or ndjson")
 
 	// Set required parameters
 	runCmd.MarkFlagRequired("agent")
-	runCmd.MarkFlagRequired("prompt")
 }
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/tk103331/eino-cli/agent"
+	"github.com/tk103331/eino-cli/usage"
+)
+
+// Output formats supported by the run command
+const (
+	outputText   = "text"
+	outputJSON   = "json"
+	outputNDJSON = "ndjson"
+)
+
+// runEvent is a single event emitted by `run --output ndjson`
+type runEvent struct {
+	Type      string       `json:"type"` // "start", "content", "tool_start", "tool_progress", "tool_end", "tool_error", "error", "end"
+	Time      time.Time    `json:"time"`
+	Agent     string       `json:"agent,omitempty"`
+	Content   string       `json:"content,omitempty"`
+	Tool      string       `json:"tool,omitempty"`
+	Arguments string       `json:"arguments,omitempty"`
+	Result    string       `json:"result,omitempty"`
+	Error     string       `json:"error,omitempty"`
+	Usage     *usage.Usage `json:"usage,omitempty"`
+	Timings   *runTimings  `json:"timings,omitempty"`
+}
+
+// runToolCall is a tool invocation reported in JSON output
+type runToolCall struct {
+	Name       string `json:"name"`
+	Arguments  string `json:"arguments,omitempty"`
+	Result     string `json:"result,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+
+	started time.Time
+	done    bool
+}
+
+// runTimings holds the phase durations of a run in milliseconds
+type runTimings struct {
+	InitMs       int64 `json:"init_ms"`
+	FirstTokenMs int64 `json:"first_token_ms,omitempty"`
+	ExecMs       int64 `json:"exec_ms"`
+	TotalMs      int64 `json:"total_ms"`
+}
+
+// runResult is the final object printed by `run --output json`
+type runResult struct {
+	Agent        string                 `json:"agent"`
+	Model        string                 `json:"model,omitempty"`
+	Prompt       string                 `json:"prompt"`
+	Answer       string                 `json:"answer"`
+	ToolCalls    []*runToolCall         `json:"tool_calls"`
+	Usage        *usage.Usage           `json:"usage,omitempty"`
+	UsageByModel map[string]usage.Usage `json:"usage_by_model,omitempty"` // Encoded with sorted model names
+	Timings      runTimings             `json:"timings"`
+	Error        string                 `json:"error,omitempty"`
+}
+
+// runRecorder collects agent callbacks into structured output
+type runRecorder struct {
+	mu        sync.Mutex
+	format    string
+	encoder   *json.Encoder
+	result    runResult
+	start     time.Time
+	execStart time.Time
+}
+
+// newRunRecorder creates a recorder writing the given format to w
+func newRunRecorder(w io.Writer, format, agentName, modelName, prompt string) *runRecorder {
+	return &runRecorder{
+		format:  format,
+		encoder: json.NewEncoder(w),
+		result: runResult{
+			Agent:     agentName,
+			Model:     modelName,
+			Prompt:    prompt,
+			ToolCalls: []*runToolCall{},
+		},
+		start: time.Now(),
+	}
+}
+
+// emit writes an event when streaming NDJSON, caller must hold the lock
+func (r *runRecorder) emit(event runEvent) {
+	if r.format != outputNDJSON {
+		return
+	}
+	event.Time = time.Now()
+	_ = r.encoder.Encode(event)
+}
+
+// begin records the start of the run
+func (r *runRecorder) begin() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.emit(runEvent{Type: "start", Agent: r.result.Agent, Content: r.result.Prompt})
+}
+
+// initialized marks the end of the initialization phase
+func (r *runRecorder) initialized() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.execStart = time.Now()
+	r.result.Timings.InitMs = r.execStart.Sub(r.start).Milliseconds()
+}
+
+// chunkCallback handles streaming output from the agent
+func (r *runRecorder) chunkCallback(chunk *agent.StreamChunk) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch chunk.Type {
+	case "content":
+		if chunk.Content == "" {
+			// Empty content marks the end of the stream
+			return
+		}
+		if r.result.Timings.FirstTokenMs == 0 {
+			r.result.Timings.FirstTokenMs = time.Since(r.execStart).Milliseconds()
+		}
+		r.result.Answer += chunk.Content
+		r.emit(runEvent{Type: "content", Content: chunk.Content})
+	case "error":
+		r.emit(runEvent{Type: "error", Error: chunk.Content})
+	}
+}
+
+// toolCallback handles tool call information from the agent
+func (r *runRecorder) toolCallback(data interface{}) {
+	info, ok := data.(agent.ToolCallInfo)
+	if !ok || agent.IsInternalNode(info.Name) {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch info.Type {
+	case "start":
+		// The Tools node and the tool itself both report the start, keep the first one
+		if r.openToolCall(info.Name) != nil {
+			return
+		}
+		r.result.ToolCalls = append(r.result.ToolCalls, &runToolCall{
+			Name:      info.Name,
+			Arguments: info.Arguments,
+			started:   time.Now(),
+		})
+		r.emit(runEvent{Type: "tool_start", Tool: info.Name, Arguments: info.Arguments})
+	case "progress":
+		r.emit(runEvent{Type: "tool_progress", Tool: info.Name, Content: info.Result})
+	case "end", "error":
+		call := r.openToolCall(info.Name)
+		if call == nil {
+			call = &runToolCall{Name: info.Name, started: time.Now()}
+			r.result.ToolCalls = append(r.result.ToolCalls, call)
+		}
+		call.done = true
+		call.DurationMs = time.Since(call.started).Milliseconds()
+		if info.Type == "error" {
+			call.Error = info.Error
+			r.emit(runEvent{Type: "tool_error", Tool: info.Name, Error: info.Error})
+		} else {
+			call.Result = info.Result
+			r.emit(runEvent{Type: "tool_end", Tool: info.Name, Result: info.Result})
+		}
+	}
+}
+
+// openToolCall finds the most recent unfinished call of the named tool, caller must hold the lock
+func (r *runRecorder) openToolCall(name string) *runToolCall {
+	for i := len(r.result.ToolCalls) - 1; i >= 0; i-- {
+		if call := r.result.ToolCalls[i]; call.Name == name && !call.done {
+			return call
+		}
+	}
+	return nil
+}
+
+// finish completes the run and writes the final output
+func (r *runRecorder) finish(reply *schema.Message, runErr error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if !r.execStart.IsZero() {
+		r.result.Timings.ExecMs = now.Sub(r.execStart).Milliseconds()
+	}
+	r.result.Timings.TotalMs = now.Sub(r.start).Milliseconds()
+
+	if reply != nil {
+		r.result.Answer = reply.Content
+	}
+	if tracker := usage.GetGlobalTracker(); tracker != nil {
+		sessionUsage := tracker.Session()
+		r.result.Usage = &sessionUsage
+		r.result.UsageByModel = tracker.ByModel()
+	}
+	if runErr != nil {
+		r.result.Error = runErr.Error()
+	}
+
+	if r.format == outputNDJSON {
+		timings := r.result.Timings
+		r.emit(runEvent{Type: "end", Content: r.result.Answer, Error: r.result.Error, Usage: r.result.Usage, Timings: &timings})
+		return nil
+	}
+
+	r.encoder.SetIndent("", "  ")
+	return r.encoder.Encode(r.result)
+}
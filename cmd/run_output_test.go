@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/tk103331/eino-cli/agent"
+)
+
+// replayRun feeds a run with a content stream, a tool call and a failing tool call to the recorder
+func replayRun(r *runRecorder, runErr error) error {
+	r.begin()
+	r.initialized()
+	for _, content := range []string{"Hel", "lo", ""} {
+		r.chunkCallback(&agent.StreamChunk{Type: "content", Content: content})
+	}
+	for _, info := range []agent.ToolCallInfo{
+		{Type: "start", Name: "ChatModel"},
+		{Type: "start", Name: "search", Arguments: `{"q":"go"}`},
+		{Type: "start", Name: "search", Arguments: `{"q":"go"}`},
+		{Type: "progress", Name: "search", Result: "fetching"},
+		{Type: "end", Name: "search", Result: "results"},
+		{Type: "start", Name: "fetch"},
+		{Type: "error", Name: "fetch", Error: "timeout"},
+		{Type: "end", Name: "Tools"},
+	} {
+		r.toolCallback(info)
+	}
+	var reply *schema.Message
+	if runErr == nil {
+		reply = schema.AssistantMessage("Hello", nil)
+	}
+	return r.finish(reply, runErr)
+}
+
+func TestRunRecorderJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		runErr     error
+		wantAnswer string
+		wantError  string
+	}{
+		{name: "success", wantAnswer: "Hello"},
+		{name: "failure", runErr: errors.New("model unavailable"), wantAnswer: "Hello", wantError: "model unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			if err := replayRun(newRunRecorder(&out, outputJSON, "helper", "gpt-4o", "hi"), tt.runErr); err != nil {
+				t.Fatal(err)
+			}
+			var result runResult
+			if err := json.Unmarshal([]byte(out.String()), &result); err != nil {
+				t.Fatalf("expected a single JSON object, got %s: %v", out.String(), err)
+			}
+			if result.Agent != "helper" || result.Model != "gpt-4o" || result.Prompt != "hi" {
+				t.Errorf("expected the run settings, got %+v", result)
+			}
+			if result.Answer != tt.wantAnswer || result.Error != tt.wantError {
+				t.Errorf("expected answer %q and error %q, got %q and %q", tt.wantAnswer, tt.wantError, result.Answer, result.Error)
+			}
+			if len(result.ToolCalls) != 2 {
+				t.Fatalf("expected 2 tool calls without internal nodes and duplicate starts, got %+v", result.ToolCalls)
+			}
+			if call := result.ToolCalls[0]; call.Name != "search" || call.Arguments != `{"q":"go"}` || call.Result != "results" {
+				t.Errorf("expected the search call, got %+v", call)
+			}
+			if call := result.ToolCalls[1]; call.Name != "fetch" || call.Error != "timeout" {
+				t.Errorf("expected the failed fetch call, got %+v", call)
+			}
+		})
+	}
+}
+
+func TestRunRecorderNDJSON(t *testing.T) {
+	var out strings.Builder
+	if err := replayRun(newRunRecorder(&out, outputNDJSON, "helper", "", "hi"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var types []string
+	var last runEvent
+	scanner := bufio.NewScanner(strings.NewReader(out.String()))
+	for scanner.Scan() {
+		var event runEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("expected one JSON event per line, got %s: %v", scanner.Text(), err)
+		}
+		if event.Time.IsZero() {
+			t.Errorf("expected event %s to have a time", event.Type)
+		}
+		types = append(types, event.Type)
+		last = event
+	}
+	want := "start,content,content,tool_start,tool_progress,tool_end,tool_start,tool_error,end"
+	if got := strings.Join(types, ","); got != want {
+		t.Errorf("expected events %s, got %s", want, got)
+	}
+	if last.Content != "Hello" || last.Timings == nil {
+		t.Errorf("expected the end event with the answer and timings, got %+v", last)
+	}
+}
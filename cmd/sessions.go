@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tk103331/eino-cli/config"
+	"github.com/tk103331/eino-cli/session"
+	"github.com/tk103331/eino-cli/ui/agent"
+)
+
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "Manage saved sessions",
+	Long:  `List, resume, delete and export interactive sessions saved under ~/.eino-cli/sessions.`,
+}
+
+var sessionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved sessions",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sessions, err := session.NewStore("").List()
+		if err != nil {
+			return err
+		}
+		if len(sessions) == 0 {
+			fmt.Println("No saved sessions")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tKIND\tNAME\tMODEL\tMESSAGES\tUPDATED\tTITLE")
+		for _, sess := range sessions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+				sess.ID, sess.Kind, sess.Name, sess.Model, len(sess.Messages),
+				sess.UpdatedAt.Format("2006-01-02 15:04"), sess.Title())
+		}
+		return w.Flush()
+	},
+}
+
+var sessionsResumeCmd = &cobra.Command{
+	Use:   "resume <id>",
+	Short: "Resume a saved session in the interactive interface",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return resumeSession(args[0])
+	},
+}
+
+var sessionsDeleteCmd = &cobra.Command{
+	Use:   "delete <id>...",
+	Short: "Delete saved sessions",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := session.NewStore("")
+		for _, id := range args {
+			if err := store.Delete(id); err != nil {
+				return err
+			}
+			fmt.Printf("Deleted session %s\n", id)
+		}
+		return nil
+	},
+}
+
+var sessionsExportCmd = &cobra.Command{
+	Use:   "export <id>",
+	Short: "Export a saved session as JSON or Markdown",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+
+		sess, err := session.NewStore("").Load(args[0])
+		if err != nil {
+			return err
+		}
+
+		out := os.Stdout
+		if output != "" && output != "-" {
+			file, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			defer file.Close()
+			out = file
+		}
+
+		return session.Export(out, sess, format)
+	},
+}
+
+// resumeSession reloads a saved session and starts the matching interactive interface
+func resumeSession(id string) error {
+	sess, err := session.NewStore("").Load(id)
+	if err != nil {
+		return err
+	}
+
+	switch sess.Kind {
+	case session.KindAgent:
+		if _, ok := config.GetConfig().Agents[sess.Name]; !ok {
+			return fmt.Errorf("Agent configuration does not exist: %s", sess.Name)
+		}
+
+		agentApp, err := agent.NewAgentApp(sess.Name, sess)
+		if err != nil {
+			return fmt.Errorf("failed to create Agent application: %w", err)
+		}
+
+		fmt.Printf("Resuming session %s with Agent %s (%d messages)...\n", sess.ID, sess.Name, len(sess.Messages))
+		if err := agentApp.Run(); err != nil {
+			return fmt.Errorf("failed to run interactive interface: %w", err)
+		}
+	case session.KindChat:
+		chatApp := agent.NewChatApp(sess.Model, sess.Tools, sess.System, sess)
+
+		fmt.Printf("Resuming session %s with Model %s (%d messages)...\n", sess.ID, sess.Model, len(sess.Messages))
+		if err := chatApp.Run(); err != nil {
+			return fmt.Errorf("failed to run chat interface: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported session kind: %s", sess.Kind)
+	}
+
+	return nil
+}
+
+func init() {
+	// Add sessions subcommand to root command
+	RootCmd.AddCommand(sessionsCmd)
+	sessionsCmd.AddCommand(sessionsListCmd, sessionsResumeCmd, sessionsDeleteCmd, sessionsExportCmd)
+
+	// Add parameters for export subcommand
+	sessionsExportCmd.Flags().StringP("format", "f", "json", "Export format: json or markdown")
+	sessionsExportCmd.Flags().StringP("output", "o", "", "Output file (defaults to stdout)")
+}
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"github.com/cloudwego/eino-ext/callbacks/langfuse"
 	"os"
+	"path/filepath"
 
 	"gopkg.in/yaml.v3"
 )
@@ -21,12 +24,16 @@ type Config struct {
 	Tools        map[string]Tool      `yaml:"tools,omitempty"`
 	Chats        map[string]Chat      `yaml:"chats,omitempty"`
 	Settings     Settings             `yaml:"settings,omitempty"`
+	Include      []string             `yaml:"include,omitempty"`
+
+	layers           []layer // Loaded files in merge order, used to locate keys
+	untrustedProject string  // Project configuration file skipped since it is not trusted
 }
 
 // Agent represents AI agent configuration
 type Agent struct {
 	System     string   `yaml:"system"`
-	Model      string   `yaml:"model"`
+	Model      string   `yaml:"model,omitempty"` // Defaults to default_model
 	Tools      []string `yaml:"tools,omitempty"`
 	MCPServers []string `yaml:"mcp_servers,omitempty"`
 }
@@ -34,25 +41,101 @@ type Agent struct {
 // Chat represents preset chat configuration
 type Chat struct {
 	System string   `yaml:"system,omitempty"`
-	Model  string   `yaml:"model"`
+	Model  string   `yaml:"model,omitempty"` // Defaults to default_model
 	Tools  []string `yaml:"tools,omitempty"`
 }
 
 // Provider represents AI provider configuration
 type Provider struct {
-	Type    string `yaml:"type"`
-	BaseURL string `yaml:"base_url,omitempty"`
-	APIKey  string `yaml:"api_key,omitempty"`
+	Type         string            `yaml:"type"`
+	BaseURL      string            `yaml:"base_url,omitempty"`
+	APIKey       string            `yaml:"api_key,omitempty"`
+	APIKeys      []APIKey          `yaml:"api_keys,omitempty"`      // Several keys shared by the provider, used instead of api_key
+	KeySelection string            `yaml:"key_selection,omitempty"` // How api_keys are picked: round_robin (default), random or weighted
+	KeyCooldown  string            `yaml:"key_cooldown,omitempty"`  // How long a key rejected with 401/429 is skipped, default 1m
+	AccessKey    string            `yaml:"access_key,omitempty"`    // Access key of providers signing requests with AK/SK, e.g. qianfan
+	SecretKey    string            `yaml:"secret_key,omitempty"`    // Secret key paired with access_key
+	Timeout      string            `yaml:"timeout,omitempty"`       // Timeout of a whole request including streaming, e.g. 60s
+	Proxy        string            `yaml:"proxy,omitempty"`         // HTTP proxy URL, defaults to HTTP_PROXY/HTTPS_PROXY
+	Headers      map[string]string `yaml:"headers,omitempty"`       // Extra HTTP headers sent with every request
+	Organization string            `yaml:"organization,omitempty"`  // OpenAI organization ID
+	APIVersion   string            `yaml:"api_version,omitempty"`   // Azure OpenAI API version
+	Deployments  map[string]string `yaml:"deployments,omitempty"`   // Azure OpenAI deployment name per model, defaults to the model name
+	Cassette     string            `yaml:"cassette,omitempty"`      // YAML or JSON file replayed by the mock provider
+}
+
+// APIKey represents one of several provider API keys, written as a plain string or as a mapping
+type APIKey struct {
+	Name   string `yaml:"name,omitempty"` // Label shown in key usage, defaults to the masked key
+	Key    string `yaml:"key"`
+	Weight int    `yaml:"weight,omitempty"` // Relative weight for weighted selection, default 1
+}
+
+// UnmarshalYAML accepts both "- sk-xxx" and "- {key: sk-xxx, weight: 2}"
+func (k *APIKey) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		k.Key = value.Value
+		return nil
+	}
+	type plain APIKey
+	return value.Decode((*plain)(k))
+}
+
+// ID returns a short hash of the key, which identifies it in key usage without revealing it
+func (k APIKey) ID() string {
+	sum := sha256.Sum256([]byte(k.Key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Label returns the name of the key, or the key masked to its last four characters
+func (k APIKey) Label() string {
+	if k.Name != "" {
+		return k.Name
+	}
+	if len(k.Key) <= 8 {
+		return "****"
+	}
+	return "****" + k.Key[len(k.Key)-4:]
 }
 
 // Model represents AI model configuration
 type Model struct {
-	Provider    string  `yaml:"provider"`
-	Model       string  `yaml:"model"`
-	MaxTokens   int     `yaml:"max_tokens,omitempty"`
-	Temperature float64 `yaml:"temperature,omitempty"`
-	TopP        float64 `yaml:"top_p,omitempty"`
-	TopK        int     `yaml:"top_k,omitempty"`
+	Provider string `yaml:"provider"`
+	Model    string `yaml:"model"`
+	// Sampling options are pointers so that an explicit zero is passed to the provider
+	MaxTokens        *int           `yaml:"max_tokens,omitempty"`
+	Temperature      *float64       `yaml:"temperature,omitempty"`
+	TopP             *float64       `yaml:"top_p,omitempty"`
+	TopK             *int           `yaml:"top_k,omitempty"`
+	Stop             []string       `yaml:"stop,omitempty"`
+	Seed             *int           `yaml:"seed,omitempty"`
+	PresencePenalty  *float64       `yaml:"presence_penalty,omitempty"`
+	FrequencyPenalty *float64       `yaml:"frequency_penalty,omitempty"`
+	ResponseFormat   string         `yaml:"response_format,omitempty"`  // text or json_object
+	ReasoningEffort  string         `yaml:"reasoning_effort,omitempty"` // low, medium or high
+	Extra            map[string]any `yaml:"extra,omitempty"`            // Provider specific settings, set on the eino-ext model config by field name
+	Pricing          *Pricing       `yaml:"pricing,omitempty"`
+	Fallback         []string       `yaml:"fallback,omitempty"` // Models to try in order when this model fails
+	Retry            *RetryPolicy   `yaml:"retry,omitempty"`
+}
+
+// RetryPolicy represents how failed model calls are retried
+type RetryPolicy struct {
+	MaxAttempts int      `yaml:"max_attempts,omitempty"` // Attempts including the first call, default 3
+	Backoff     string   `yaml:"backoff,omitempty"`      // Delay before the first retry, doubled after each attempt, default 1s
+	MaxBackoff  string   `yaml:"max_backoff,omitempty"`  // Upper bound of the delay, default 30s
+	RetryOn     []string `yaml:"retry_on,omitempty"`     // Errors to retry: status codes such as 429 or 503, classes such as 5xx, or timeout; default [429, 5xx, timeout]
+}
+
+// Pricing represents model pricing per million tokens, used for cost accounting
+type Pricing struct {
+	Input  float64 `yaml:"input"`  // Price per million prompt tokens
+	Output float64 `yaml:"output"` // Price per million completion tokens
+}
+
+// Cost calculates the cost of a model call
+func (p *Pricing) Cost(promptTokens, completionTokens int) float64 {
+	return (float64(promptTokens)*p.Input + float64(completionTokens)*p.Output) / 1_000_000
 }
 
 // MCPServer represents MCP server configuration
@@ -65,6 +148,9 @@ type MCPServer struct {
 	// for sse & streamable-http
 	URL     string            `yaml:"url,omitempty"`
 	Headers map[string]string `yaml:"headers,omitempty"`
+	// Approval of the server's tool calls, see Tool.Approval
+	Approval      string         `yaml:"approval,omitempty"`
+	ApprovalRules *ApprovalRules `yaml:"approval_rules,omitempty"`
 }
 
 // Tool represents tool configuration
@@ -73,6 +159,16 @@ type Tool struct {
 	Description string           `yaml:"description,omitempty"`
 	Config      map[string]Value `yaml:"config,omitempty"`
 	Params      []ToolParam      `yaml:"params,omitempty"`
+	// Approval is the policy for calls of the tool: always (default), never, ask or pattern
+	Approval      string         `yaml:"approval,omitempty"`
+	ApprovalRules *ApprovalRules `yaml:"approval_rules,omitempty"` // Used by the pattern policy
+}
+
+// ApprovalRules decide tool calls by regular expressions matched against the JSON arguments,
+// compacted with sorted keys. Deny rules win over allow rules, calls matching neither are asked.
+type ApprovalRules struct {
+	Allow []string `yaml:"allow,omitempty"`
+	Deny  []string `yaml:"deny,omitempty"`
 }
 
 // ToolParam represents tool parameter configuration
@@ -86,15 +182,49 @@ type ToolParam struct {
 // Settings global settings
 type Settings struct {
 	Langfuse *langfuse.Config
+	Cache    *CacheSettings `yaml:"cache,omitempty"`
 }
 
-// LoadConfig loads configuration from file and saves to global variable
+// CacheSettings represents the on-disk cache of model responses
+type CacheSettings struct {
+	Enabled bool   `yaml:"enabled"`
+	Dir     string `yaml:"dir,omitempty"`      // Default ~/.eino-cli/cache
+	TTL     string `yaml:"ttl,omitempty"`      // How long a response is reused, default 24h
+	MaxSize int    `yaml:"max_size,omitempty"` // Size limit in MB, oldest entries are removed first, default 100
+}
+
+// LoadConfig loads configuration from file and its includes, and saves to global variable
 func LoadConfig(configPath string) (*Config, error) {
+	cfg, err := loadFile(configPath, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Save to global variable
+	globalConfig = cfg
+
+	return cfg, nil
+}
+
+// loadFile loads a single configuration file, merged on top of the files it includes.
+// loading holds the files currently being loaded, to detect include cycles.
+func loadFile(configPath string, loading map[string]bool) (*Config, error) {
 	// Check if configuration file exists
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("configuration file does not exist: %s", configPath)
 	}
 
+	// Guard against include cycles
+	absPath, err := filepath.Abs(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve configuration file path: %w", err)
+	}
+	if loading[absPath] {
+		return nil, fmt.Errorf("configuration file includes itself: %s", configPath)
+	}
+	loading[absPath] = true
+	defer delete(loading, absPath)
+
 	// Read configuration file
 	data, err := os.ReadFile(configPath)
 	if err != nil {
@@ -102,15 +232,58 @@ func LoadConfig(configPath string) (*Config, error) {
 	}
 
 	// Parse YAML
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration file %s: %w", configPath, err)
+	}
+
+	// Expand environment variable and secret file references
+	if err := expandConfig(&root, configPath); err != nil {
+		return nil, fmt.Errorf("failed to expand configuration file: %w", err)
+	}
+
 	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse configuration file: %w", err)
+	if len(root.Content) > 0 {
+		if err := root.Decode(&cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse configuration file %s: %w", configPath, err)
+		}
 	}
 
-	// Save to global variable
-	globalConfig = &cfg
+	cfg.layers = []layer{{file: configPath, root: &root}}
+	cfg.bindValueFiles()
+
+	// Included files are merged first, so that the including file overrides them
+	merged := &Config{}
+	for _, include := range cfg.Include {
+		paths, err := resolveInclude(configPath, include)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			included, err := loadFile(path, loading)
+			if err != nil {
+				return nil, err
+			}
+			merged.Merge(included)
+		}
+	}
+	merged.Merge(&cfg)
 
-	return &cfg, nil
+	return merged, nil
+}
+
+// ResolveModel returns the model name to use, falling back to default_model when name is empty
+func (c *Config) ResolveModel(name string) (string, error) {
+	if name == "" {
+		name = c.DefaultModel
+	}
+	if name == "" {
+		return "", fmt.Errorf("no model specified and default_model is not set")
+	}
+	if _, ok := c.Models[name]; !ok {
+		return "", fmt.Errorf("model configuration does not exist: %s", name)
+	}
+	return name, nil
 }
 
 // GetConfig gets global configuration
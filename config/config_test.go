@@ -0,0 +1,45 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveModel(t *testing.T) {
+	models := map[string]Model{
+		"fast":  {Provider: "p", Model: "fast"},
+		"smart": {Provider: "p", Model: "smart"},
+	}
+	tests := []struct {
+		name         string
+		defaultModel string
+		model        string
+		want         string
+		wantErr      string
+	}{
+		{name: "explicit", defaultModel: "fast", model: "smart", want: "smart"},
+		{name: "default", defaultModel: "fast", want: "fast"},
+		{name: "explicit without default", model: "smart", want: "smart"},
+		{name: "unknown", defaultModel: "fast", model: "missing", wantErr: "model configuration does not exist: missing"},
+		{name: "unknown default", defaultModel: "missing", wantErr: "model configuration does not exist: missing"},
+		{name: "no default", wantErr: "default_model is not set"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{DefaultModel: tt.defaultModel, Models: models}
+			got, err := cfg.ResolveModel(tt.model)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("expected model %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
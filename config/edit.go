@@ -0,0 +1,214 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParsePath splits a dotted key path such as models.gpt4.temperature.
+// Keys containing dots can be quoted: models."gpt-4.1".temperature
+func ParsePath(path string) ([]string, error) {
+	var (
+		keys    []string
+		current strings.Builder
+		quoted  bool
+	)
+	for _, r := range path {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == '.' && !quoted:
+			keys = append(keys, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in path: %s", path)
+	}
+	keys = append(keys, current.String())
+	for _, key := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("invalid path: %s", path)
+		}
+	}
+	return keys, nil
+}
+
+// LookupPath finds the node at the key path; sequence items are addressed by index
+func LookupPath(node *yaml.Node, path []string) (*yaml.Node, error) {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	for i, key := range path {
+		child, err := childNode(node, key)
+		if err != nil {
+			return nil, err
+		}
+		if child == nil {
+			return nil, fmt.Errorf("key does not exist: %s", strings.Join(path[:i+1], "."))
+		}
+		node = child
+	}
+	return node, nil
+}
+
+// SetValue sets the key path in a configuration file to a YAML value, keeping comments and formatting
+// of the rest of the file. Missing intermediate keys are created; the file is created if it does not exist.
+func SetValue(file string, path []string, value string) error {
+	if len(path) == 0 {
+		return errors.New("path cannot be empty")
+	}
+
+	var doc yaml.Node
+	data, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read configuration file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse configuration file: %w", err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+
+	newNode, err := parseValue(value)
+	if err != nil {
+		return err
+	}
+
+	// Walk to the parent of the last key, creating mappings on the way
+	node := doc.Content[0]
+	for i, key := range path[:len(path)-1] {
+		child, err := childNode(node, key)
+		if err != nil {
+			return err
+		}
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			if node.Kind != yaml.MappingNode {
+				return fmt.Errorf("cannot create key %s: parent is not a mapping", strings.Join(path[:i+1], "."))
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+		}
+		node = child
+	}
+
+	last := path[len(path)-1]
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == last {
+				keepComments(node.Content[i+1], newNode)
+				node.Content[i+1] = newNode
+				return writeNode(file, &doc)
+			}
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: last}, newNode)
+	case yaml.SequenceNode:
+		index, err := strconv.Atoi(last)
+		if err != nil || index < 0 || index > len(node.Content) {
+			return fmt.Errorf("invalid index %s for list of %d items", last, len(node.Content))
+		}
+		if index == len(node.Content) {
+			node.Content = append(node.Content, newNode)
+		} else {
+			keepComments(node.Content[index], newNode)
+			node.Content[index] = newNode
+		}
+	default:
+		return fmt.Errorf("cannot set key %s: parent is not a mapping or list", strings.Join(path, "."))
+	}
+	return writeNode(file, &doc)
+}
+
+// childNode returns the child of a mapping by key or of a sequence by index, or nil if it does not exist
+func childNode(node *yaml.Node, key string) (*yaml.Node, error) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				return node.Content[i+1], nil
+			}
+		}
+		return nil, nil
+	case yaml.SequenceNode:
+		index, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("list items must be addressed by index, got %s", key)
+		}
+		if index < 0 || index >= len(node.Content) {
+			return nil, nil
+		}
+		return node.Content[index], nil
+	case yaml.AliasNode:
+		return childNode(node.Alias, key)
+	default:
+		return nil, fmt.Errorf("cannot look up %s in a scalar value", key)
+	}
+}
+
+// parseValue parses a command line value as YAML, so that numbers, booleans and lists keep their types
+func parseValue(value string) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &doc); err != nil {
+		return nil, fmt.Errorf("invalid value: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	}
+	node := doc.Content[0]
+	node.Line, node.Column = 0, 0
+	return node, nil
+}
+
+// keepComments carries the comments of a replaced node over to its replacement
+func keepComments(old, replacement *yaml.Node) {
+	if replacement.HeadComment == "" {
+		replacement.HeadComment = old.HeadComment
+	}
+	if replacement.LineComment == "" {
+		replacement.LineComment = old.LineComment
+	}
+	if replacement.FootComment == "" {
+		replacement.FootComment = old.FootComment
+	}
+}
+
+// writeNode atomically writes a YAML document to file, keeping its permissions
+func writeNode(file string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode configuration file: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode configuration file: %w", err)
+	}
+
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(file); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("failed to create configuration directory: %w", err)
+	}
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), mode); err != nil {
+		return fmt.Errorf("failed to write configuration file: %w", err)
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write configuration file: %w", err)
+	}
+	return nil
+}
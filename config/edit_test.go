@@ -0,0 +1,120 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    []string
+		wantErr bool
+	}{
+		{path: "models.gpt4.temperature", want: []string{"models", "gpt4", "temperature"}},
+		{path: `models."gpt-4.1".temperature`, want: []string{"models", "gpt-4.1", "temperature"}},
+		{path: "agents.helper.tools.0", want: []string{"agents", "helper", "tools", "0"}},
+		{path: "models..model", wantErr: true},
+		{path: `models."gpt`, wantErr: true},
+		{path: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := ParsePath(tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSetValue(t *testing.T) {
+	const original = `# Models
+models:
+  gpt4:
+    provider: openai # main provider
+    model: gpt-4o
+agents:
+  helper:
+    tools: [search]
+`
+	tests := []struct {
+		name    string
+		path    string
+		value   string
+		want    []string // Expected substrings of the written file
+		wantErr string
+	}{
+		{name: "replace keeps comments", path: "models.gpt4.provider", value: "azure", want: []string{"# Models", "provider: azure # main provider", "model: gpt-4o"}},
+		{name: "typed value", path: "models.gpt4.temperature", value: "0.2", want: []string{"temperature: 0.2"}},
+		{name: "string value", path: "models.gpt4.model", value: "'0.2'", want: []string{`model: '0.2'`}},
+		{name: "list value", path: "models.gpt4.stop", value: "[END, STOP]", want: []string{"stop: [END, STOP]"}},
+		{name: "create intermediate keys", path: `models."gpt-4.1".model`, value: "gpt-4.1", want: []string{"gpt-4.1:\n    model: gpt-4.1"}},
+		{name: "replace list item", path: "agents.helper.tools.0", value: "wiki", want: []string{"tools: [wiki]"}},
+		{name: "append list item", path: "agents.helper.tools.1", value: "wiki", want: []string{"tools: [search, wiki]"}},
+		{name: "index out of range", path: "agents.helper.tools.5", value: "wiki", wantErr: "invalid index"},
+		{name: "list key", path: "agents.helper.tools.first", value: "wiki", wantErr: "invalid index"},
+		{name: "scalar parent", path: "models.gpt4.model.name", value: "x", wantErr: "not a mapping or list"},
+		{name: "invalid value", path: "models.gpt4.model", value: "[unclosed", wantErr: "invalid value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, t.TempDir(), "config.yml", original)
+			keys, err := ParsePath(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = SetValue(path, keys, tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("expected %q in the file:\n%s", want, data)
+				}
+			}
+			if _, err := LoadConfig(path); err != nil {
+				t.Errorf("expected the edited file to load: %v", err)
+			}
+		})
+	}
+}
+
+func TestSetValueNewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "new", "config.yml")
+	if err := SetValue(path, []string{"default_model"}, "gpt4"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected a new configuration file to be private, got %v", info.Mode().Perm())
+	}
+	data, _ := os.ReadFile(path)
+	if strings.TrimSpace(string(data)) != "default_model: gpt4" {
+		t.Errorf("expected the new key, got %q", data)
+	}
+}
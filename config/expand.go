@@ -0,0 +1,151 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// refPattern matches ${...} references; a leading $$ escapes the reference
+var refPattern = regexp.MustCompile(`\$?\$\{([^}]*)\}`)
+
+// expandConfig expands references in a parsed configuration file, except in the command templates
+// of customexec tools, which are run by a shell that resolves ${VAR} itself
+func expandConfig(root *yaml.Node, file string) error {
+	return expandNode(root, file, execTemplates(root))
+}
+
+// expandNode expands environment and file references in every scalar value of the node tree,
+// skipping the given nodes. Mapping keys are left untouched.
+func expandNode(node *yaml.Node, file string, skip map[*yaml.Node]bool) error {
+	if node == nil || skip[node] {
+		return nil
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return expandScalar(node, file)
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := expandNode(node.Content[i], file, skip); err != nil {
+				return err
+			}
+		}
+	default:
+		for _, child := range node.Content {
+			if err := expandNode(child, file, skip); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// execTemplates returns the cmd and args nodes of the customexec tools in a configuration file
+func execTemplates(root *yaml.Node) map[*yaml.Node]bool {
+	templates := make(map[*yaml.Node]bool)
+	tools, depth := locate(root, []string{"tools"})
+	if depth == 0 || tools.Kind != yaml.MappingNode {
+		return templates
+	}
+	for i := 1; i < len(tools.Content); i += 2 {
+		toolNode := tools.Content[i]
+		if toolNode.Kind != yaml.MappingNode {
+			continue
+		}
+		typ := lookupNode(toolNode, "type")
+		cfg := lookupNode(toolNode, "config")
+		if typ == nil || !strings.EqualFold(typ.Value, "customexec") || cfg == nil {
+			continue
+		}
+		for _, key := range []string{"cmd", "args"} {
+			if node := lookupNode(cfg, key); node != nil {
+				templates[node] = true
+			}
+		}
+	}
+	return templates
+}
+
+// expandScalar expands references in a scalar node in place
+func expandScalar(node *yaml.Node, file string) error {
+	if !strings.Contains(node.Value, "${") {
+		return nil
+	}
+	value, err := expandString(node.Value)
+	if err != nil {
+		return fmt.Errorf("%s:%d:%d: %w", file, node.Line, node.Column, err)
+	}
+	node.Value = value
+	// Let plain scalars resolve their type again, so that "${MAX_TOKENS}" can still decode into an int
+	if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+		node.Tag = ""
+	}
+	return nil
+}
+
+// expandString expands ${ENV_VAR}, ${env:NAME:-default} and ${file:/path} references in s.
+// $${...} is kept literally as ${...}.
+func expandString(s string) (string, error) {
+	var firstErr error
+	result := refPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+		if firstErr != nil {
+			return match
+		}
+		value, err := resolveRef(match[2 : len(match)-1])
+		if err != nil {
+			firstErr = err
+			return match
+		}
+		return value
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return result, nil
+}
+
+// resolveRef resolves the body of a single ${...} reference
+func resolveRef(ref string) (string, error) {
+	if path, ok := strings.CutPrefix(ref, "file:"); ok {
+		return readSecretFile(path)
+	}
+	ref = strings.TrimPrefix(ref, "env:")
+
+	name, def, hasDefault := strings.Cut(ref, ":-")
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("invalid reference ${%s}: missing variable name", ref)
+	}
+	if value, ok := os.LookupEnv(name); ok && value != "" {
+		return value, nil
+	}
+	if hasDefault {
+		return def, nil
+	}
+	return "", fmt.Errorf("required environment variable %s is not set", name)
+}
+
+// readSecretFile reads a secret from a file, trimming the trailing newline
+func readSecretFile(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", fmt.Errorf("invalid reference ${file:}: missing file path")
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file %s: %w", path, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ProjectConfigFile is the project-local configuration file, looked up from the current directory upwards
+	ProjectConfigFile = ".eino-cli.yml"
+	// ConfigPathEnv lists extra configuration files, separated by the OS path list separator
+	ConfigPathEnv = "EINO_CLI_CONFIG"
+)
+
+// layer is a single loaded configuration file
+type layer struct {
+	file string
+	root *yaml.Node
+}
+
+// LoadLayeredConfig loads the user configuration file, the project configuration file and
+// the files listed in EINO_CLI_CONFIG, merges them in that order and saves the result to global variable.
+// The project configuration file is skipped unless it was trusted with TrustProject, see UntrustedProject.
+// A missing user configuration file is only an error when no other layer exists.
+func LoadLayeredConfig(userPath string) (*Config, error) {
+	var paths []string
+	seen := map[string]bool{}
+	addPath := func(path string) {
+		absPath, err := filepath.Abs(path)
+		if err != nil || seen[absPath] {
+			return
+		}
+		seen[absPath] = true
+		paths = append(paths, path)
+	}
+
+	if _, err := os.Stat(userPath); err == nil {
+		addPath(userPath)
+	}
+	var untrusted string
+	if projectPath := FindProjectConfig(); projectPath != "" {
+		trusted, err := projectTrusted(userPath, projectPath)
+		if err != nil {
+			return nil, err
+		}
+		if trusted {
+			addPath(projectPath)
+		} else {
+			untrusted = projectPath
+		}
+	}
+	for _, path := range filepath.SplitList(os.Getenv(ConfigPathEnv)) {
+		if path = strings.TrimSpace(path); path != "" {
+			addPath(path)
+		}
+	}
+	if len(paths) == 0 {
+		if untrusted != "" {
+			return nil, fmt.Errorf("configuration file does not exist: %s, and project configuration file %s is not trusted", userPath, untrusted)
+		}
+		return nil, fmt.Errorf("configuration file does not exist: %s", userPath)
+	}
+
+	cfg := &Config{}
+	for _, path := range paths {
+		layerCfg, err := loadFile(path, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+		cfg.Merge(layerCfg)
+	}
+	cfg.untrustedProject = untrusted
+
+	// Save to global variable
+	globalConfig = cfg
+
+	return cfg, nil
+}
+
+// Merge overrides the configuration with another one.
+// Entries of the named maps are replaced per key, scalar settings are replaced when set.
+func (c *Config) Merge(other *Config) {
+	c.Agents = mergeMap(c.Agents, other.Agents)
+	c.Providers = mergeMap(c.Providers, other.Providers)
+	c.Models = mergeMap(c.Models, other.Models)
+	c.MCPServers = mergeMap(c.MCPServers, other.MCPServers)
+	c.Tools = mergeMap(c.Tools, other.Tools)
+	c.Chats = mergeMap(c.Chats, other.Chats)
+	if other.DefaultModel != "" {
+		c.DefaultModel = other.DefaultModel
+	}
+	if other.Settings.Langfuse != nil {
+		c.Settings.Langfuse = other.Settings.Langfuse
+	}
+	if other.Settings.Cache != nil {
+		c.Settings.Cache = other.Settings.Cache
+	}
+	c.layers = append(c.layers, other.layers...)
+}
+
+// mergeMap copies the entries of src into dst, replacing existing keys
+func mergeMap[T any](dst, src map[string]T) map[string]T {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]T, len(src))
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+// resolveInclude resolves an include entry relative to the including file; glob patterns are expanded
+func resolveInclude(configPath, include string) ([]string, error) {
+	if rest, ok := strings.CutPrefix(include, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			include = filepath.Join(home, rest)
+		}
+	}
+	if !filepath.IsAbs(include) {
+		include = filepath.Join(filepath.Dir(configPath), include)
+	}
+	if !strings.ContainsAny(include, "*?[") {
+		return []string{include}, nil
+	}
+	paths, err := filepath.Glob(include)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include pattern in %s: %w", configPath, err)
+	}
+	return paths, nil
+}
+
+// UntrustedProject returns the project configuration file that was found but skipped since it is not trusted
+func (c *Config) UntrustedProject() string {
+	return c.untrustedProject
+}
+
+// FindProjectConfig looks for the project configuration file from the current directory upwards
+func FindProjectConfig() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, ProjectConfigFile)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile writes a configuration file below dir and returns its path
+func writeFile(t *testing.T, dir, name, data string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadLayeredConfigPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "user/shared.yml", `default_model: included
+models:
+  included: {provider: p, model: included}
+  shared: {provider: p, model: from-include}
+`)
+	userPath := writeFile(t, dir, "user/config.yml", `include: [shared.yml]
+default_model: user
+providers:
+  p: {type: openai, api_key: user-key}
+models:
+  user: {provider: p, model: user}
+  shared: {provider: p, model: from-user}
+`)
+	projectPath := writeFile(t, dir, "project/"+ProjectConfigFile, `default_model: project
+models:
+  shared: {provider: p, model: from-project}
+`)
+	envPath := writeFile(t, dir, "env.yml", `models:
+  shared: {provider: p, model: from-env}
+`)
+	if err := TrustProject(userPath, projectPath); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(filepath.Join(dir, "project"))
+
+	tests := []struct {
+		name        string
+		envPath     string
+		wantDefault string
+		wantShared  string
+	}{
+		{"project overrides user and includes", "", "project", "from-project"},
+		{"EINO_CLI_CONFIG overrides project", envPath, "project", "from-env"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ConfigPathEnv, tt.envPath)
+			cfg, err := LoadLayeredConfig(userPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.DefaultModel != tt.wantDefault {
+				t.Errorf("expected default_model %s, got %s", tt.wantDefault, cfg.DefaultModel)
+			}
+			if got := cfg.Models["shared"].Model; got != tt.wantShared {
+				t.Errorf("expected model shared from %s, got %s", tt.wantShared, got)
+			}
+			// Entries of the lower layers are kept
+			for _, name := range []string{"included", "user"} {
+				if _, ok := cfg.Models[name]; !ok {
+					t.Errorf("expected model %s of a lower layer to be kept", name)
+				}
+			}
+			if cfg.Providers["p"].APIKey != "user-key" {
+				t.Errorf("expected the provider of the user file, got %+v", cfg.Providers["p"])
+			}
+		})
+	}
+}
+
+func TestLoadLayeredConfigProjectTrust(t *testing.T) {
+	dir := t.TempDir()
+	userPath := writeFile(t, dir, "user/config.yml", `providers:
+  p: {type: openai, base_url: https://api.openai.com/v1}
+`)
+	project := `providers:
+  p: {type: openai, base_url: https://attacker.example.test}
+`
+	projectPath := writeFile(t, dir, "repo/"+ProjectConfigFile, project)
+	t.Chdir(filepath.Join(dir, "repo"))
+	t.Setenv(ConfigPathEnv, "")
+
+	load := func() *Config {
+		t.Helper()
+		cfg, err := LoadLayeredConfig(userPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cfg
+	}
+
+	cfg := load()
+	if got := cfg.Providers["p"].BaseURL; got != "https://api.openai.com/v1" {
+		t.Errorf("expected the untrusted project file to be skipped, got base_url %s", got)
+	}
+	if cfg.UntrustedProject() != projectPath {
+		t.Errorf("expected %s to be reported as untrusted, got %q", projectPath, cfg.UntrustedProject())
+	}
+
+	if err := TrustProject(userPath, projectPath); err != nil {
+		t.Fatal(err)
+	}
+	cfg = load()
+	if got := cfg.Providers["p"].BaseURL; got != "https://attacker.example.test" {
+		t.Errorf("expected the trusted project file to be merged, got base_url %s", got)
+	}
+	if cfg.UntrustedProject() != "" {
+		t.Errorf("expected no untrusted project file, got %s", cfg.UntrustedProject())
+	}
+
+	// A change revokes the trust
+	writeFile(t, dir, "repo/"+ProjectConfigFile, project+"default_model: other\n")
+	if cfg = load(); cfg.DefaultModel != "" || cfg.UntrustedProject() != projectPath {
+		t.Errorf("expected the changed project file to be skipped, got default_model %q", cfg.DefaultModel)
+	}
+
+	if err := TrustProject(userPath, projectPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := UntrustProject(userPath, projectPath); err != nil {
+		t.Fatal(err)
+	}
+	if cfg = load(); cfg.UntrustedProject() != projectPath {
+		t.Error("expected the untrusted project file to be skipped")
+	}
+
+	// Without a user file the untrusted project file is named in the error
+	if _, err := LoadLayeredConfig(filepath.Join(dir, "missing", "config.yml")); err == nil || !strings.Contains(err.Error(), "not trusted") {
+		t.Errorf("expected an error naming the untrusted project file, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File returns the path of the first loaded configuration file
+func (c *Config) File() string {
+	if len(c.layers) == 0 {
+		return ""
+	}
+	return c.layers[0].file
+}
+
+// Files returns the paths of all loaded configuration files in merge order
+func (c *Config) Files() []string {
+	files := make([]string, len(c.layers))
+	for i, l := range c.layers {
+		files[i] = l.file
+	}
+	return files
+}
+
+// Location returns the position of a key path such as ("agents", "coder", "model") as file:line:col.
+// If the full path does not exist, the position of the deepest existing key is returned.
+func (c *Config) Location(path ...string) string {
+	file, line, col := c.position(path...)
+	return fmt.Sprintf("%s:%d:%d", file, line, col)
+}
+
+// position finds the file, line and column of the deepest existing key of the path.
+// Entries are located in the last file that defines them, matching merge order.
+func (c *Config) position(path ...string) (string, int, int) {
+	entryDepth := min(len(path), 2)
+	var (
+		best      *yaml.Node
+		bestFile  string
+		bestDepth = -1
+	)
+	for i := len(c.layers) - 1; i >= 0; i-- {
+		node, depth := locate(c.layers[i].root, path)
+		if node == nil {
+			continue
+		}
+		if depth >= entryDepth {
+			return c.layers[i].file, node.Line, node.Column
+		}
+		if depth > bestDepth {
+			best, bestFile, bestDepth = node, c.layers[i].file, depth
+		}
+	}
+	if best == nil {
+		return c.File(), 0, 0
+	}
+	return bestFile, best.Line, best.Column
+}
+
+// locate walks the key path from the document root, returning the deepest node found and its depth
+func locate(root *yaml.Node, path []string) (*yaml.Node, int) {
+	node := root
+	if node == nil {
+		return nil, 0
+	}
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil, 0
+		}
+		node = node.Content[0]
+	}
+	depth := 0
+	for _, key := range path {
+		next := lookupNode(node, key)
+		if next == nil {
+			break
+		}
+		node = next
+		depth++
+	}
+	return node, depth
+}
+
+// lookupNode finds the child of a mapping node by key, or of a sequence node by scalar value
+func lookupNode(node *yaml.Node, key string) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				return node.Content[i+1]
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if item.Kind == yaml.ScalarNode && item.Value == key {
+				return item
+			}
+		}
+	}
+	return nil
+}
+
+// bindValueFiles records the configuration file in tool config values, so that they can report their location
+func (c *Config) bindValueFiles() {
+	file := c.File()
+	for _, t := range c.Tools {
+		for key, value := range t.Config {
+			value.file = file
+			t.Config[key] = value
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TrustFileName records the trusted project configuration files, next to the user configuration file.
+// A project configuration file comes with a checked out repository and can point providers at other endpoints,
+// expand local files and secrets and start commands, so it is only loaded once the user trusted its content.
+const TrustFileName = "trusted_projects.yml"
+
+// trustFile returns the path of the file recording the trusted project configuration files
+func trustFile(userPath string) string {
+	return filepath.Join(filepath.Dir(userPath), TrustFileName)
+}
+
+// readTrusted returns the trusted project configuration files with the hash of their trusted content
+func readTrusted(userPath string) (map[string]string, error) {
+	data, err := os.ReadFile(trustFile(userPath))
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trusted projects: %w", err)
+	}
+	trusted := map[string]string{}
+	if err := yaml.Unmarshal(data, &trusted); err != nil {
+		return nil, fmt.Errorf("failed to parse trusted projects %s: %w", trustFile(userPath), err)
+	}
+	if trusted == nil {
+		trusted = map[string]string{}
+	}
+	return trusted, nil
+}
+
+// writeTrusted saves the trusted project configuration files
+func writeTrusted(userPath string, trusted map[string]string) error {
+	data, err := yaml.Marshal(trusted)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(trustFile(userPath)), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(trustFile(userPath), data, 0600)
+}
+
+// fileHash returns the absolute path and the content hash of a file
+func fileHash(path string) (string, string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", err
+	}
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256(data)
+	return absPath, hex.EncodeToString(sum[:]), nil
+}
+
+// TrustProject records the current content of a project configuration file as trusted
+func TrustProject(userPath, projectPath string) error {
+	absPath, hash, err := fileHash(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to read project configuration file: %w", err)
+	}
+	trusted, err := readTrusted(userPath)
+	if err != nil {
+		return err
+	}
+	trusted[absPath] = hash
+	return writeTrusted(userPath, trusted)
+}
+
+// UntrustProject removes a project configuration file from the trusted ones
+func UntrustProject(userPath, projectPath string) error {
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return err
+	}
+	trusted, err := readTrusted(userPath)
+	if err != nil {
+		return err
+	}
+	if _, ok := trusted[absPath]; !ok {
+		return fmt.Errorf("project configuration file is not trusted: %s", absPath)
+	}
+	delete(trusted, absPath)
+	return writeTrusted(userPath, trusted)
+}
+
+// projectTrusted reports whether a project configuration file is trusted with its current content
+func projectTrusted(userPath, projectPath string) (bool, error) {
+	trusted, err := readTrusted(userPath)
+	if err != nil {
+		return false, err
+	}
+	absPath, hash, err := fileHash(projectPath)
+	if err != nil {
+		return false, err
+	}
+	return trusted[absPath] == hash, nil
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Diagnostic describes a problem found in the configuration file
+type Diagnostic struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// String formats the diagnostic as "file:line:col: message"
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", d.File, d.Line, d.Column, d.Message)
+}
+
+// Diagnostics collects configuration problems
+type Diagnostics []Diagnostic
+
+// Add records a problem at the given key path
+func (d *Diagnostics) Add(cfg *Config, path []string, format string, args ...interface{}) {
+	file, line, col := cfg.position(path...)
+	*d = append(*d, Diagnostic{
+		File:    file,
+		Line:    line,
+		Column:  col,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Sort orders diagnostics by file and position
+func (d Diagnostics) Sort() {
+	sort.SliceStable(d, func(i, j int) bool {
+		if d[i].File != d[j].File {
+			return d[i].File < d[j].File
+		}
+		if d[i].Line != d[j].Line {
+			return d[i].Line < d[j].Line
+		}
+		return d[i].Column < d[j].Column
+	})
+}
+
+// Validate checks cross-references between agents, chats, models, providers, tools and MCP servers.
+// Type-specific settings are checked by the packages that own them.
+func Validate(cfg *Config) Diagnostics {
+	var diags Diagnostics
+
+	if cfg.DefaultModel != "" {
+		if _, ok := cfg.Models[cfg.DefaultModel]; !ok {
+			diags.Add(cfg, []string{"default_model"}, "default_model references undefined model %q", cfg.DefaultModel)
+		}
+	}
+
+	for _, name := range sortedKeys(cfg.Providers) {
+		if cfg.Providers[name].Type == "" {
+			diags.Add(cfg, []string{"providers", name}, "provider %q must specify type", name)
+		}
+	}
+
+	for _, name := range sortedKeys(cfg.Models) {
+		m := cfg.Models[name]
+		path := []string{"models", name}
+		if m.Model == "" {
+			diags.Add(cfg, path, "model %q must specify model", name)
+		}
+		if m.Provider == "" {
+			diags.Add(cfg, path, "model %q must specify provider", name)
+		} else if _, ok := cfg.Providers[m.Provider]; !ok {
+			diags.Add(cfg, append(path, "provider"), "model %q references undefined provider %q", name, m.Provider)
+		}
+		for _, fallback := range m.Fallback {
+			if _, ok := cfg.Models[fallback]; !ok {
+				diags.Add(cfg, append(path, "fallback", fallback), "model %q references undefined fallback model %q", name, fallback)
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(cfg.Agents) {
+		a := cfg.Agents[name]
+		path := []string{"agents", name}
+		diags.checkModelRef(cfg, path, "agent", name, a.Model)
+		diags.checkToolRefs(cfg, path, "agent", name, a.Tools)
+		for _, server := range a.MCPServers {
+			if _, ok := cfg.MCPServers[server]; !ok {
+				diags.Add(cfg, append(path, "mcp_servers", server), "agent %q references undefined MCP server %q", name, server)
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(cfg.Chats) {
+		c := cfg.Chats[name]
+		path := []string{"chats", name}
+		diags.checkModelRef(cfg, path, "chat", name, c.Model)
+		diags.checkToolRefs(cfg, path, "chat", name, c.Tools)
+	}
+
+	for _, name := range sortedKeys(cfg.Tools) {
+		if cfg.Tools[name].Type == "" {
+			diags.Add(cfg, []string{"tools", name}, "tool %q must specify type", name)
+		}
+	}
+
+	return diags
+}
+
+// checkModelRef checks that an agent or chat references an existing model
+func (d *Diagnostics) checkModelRef(cfg *Config, path []string, kind, name, model string) {
+	if model == "" {
+		if cfg.DefaultModel == "" {
+			d.Add(cfg, path, "%s %q must specify model when default_model is not set", kind, name)
+		}
+		return
+	}
+	if _, ok := cfg.Models[model]; !ok {
+		d.Add(cfg, append(path, "model"), "%s %q references undefined model %q", kind, name, model)
+	}
+}
+
+// checkToolRefs checks that an agent or chat references existing tools
+func (d *Diagnostics) checkToolRefs(cfg *Config, path []string, kind, name string, tools []string) {
+	for _, t := range tools {
+		if _, ok := cfg.Tools[t]; !ok {
+			d.Add(cfg, append(path, "tools", t), "%s %q references undefined tool %q", kind, name, t)
+		}
+	}
+}
+
+// sortedKeys returns map keys in a stable order, so that diagnostics are reported deterministically
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "tools.yml", `tools:
+  search: {type: duckduckgo}
+  broken: {}
+`)
+	path := writeFile(t, dir, "config.yml", `include: [tools.yml]
+default_model: missing
+providers:
+  p: {type: openai}
+  untyped: {api_key: x}
+models:
+  m:
+    provider: nowhere
+    model: gpt-4o
+    fallback: [m, ghost]
+  empty: {}
+agents:
+  helper:
+    model: m
+    tools: [search, lost]
+    mcp_servers: [fs]
+chats:
+  quick:
+    tools: [search]
+`)
+	// The chat uses default_model, whose reference is reported once
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diags := Validate(cfg)
+	diags.Sort()
+
+	want := []string{
+		"config.yml:2:16: default_model references undefined model \"missing\"",
+		"config.yml:5:12: provider \"untyped\" must specify type",
+		"config.yml:8:15: model \"m\" references undefined provider \"nowhere\"",
+		"config.yml:10:19: model \"m\" references undefined fallback model \"ghost\"",
+		"config.yml:11:10: model \"empty\" must specify model",
+		"config.yml:11:10: model \"empty\" must specify provider",
+		"config.yml:15:21: agent \"helper\" references undefined tool \"lost\"",
+		"config.yml:16:19: agent \"helper\" references undefined MCP server \"fs\"",
+		"tools.yml:3:11: tool \"broken\" must specify type",
+	}
+	var got []string
+	for _, d := range diags {
+		d.File = filepath.Base(d.File)
+		got = append(got, d.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected diagnostics\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestValidateDefaultModel(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "config.yml", `default_model: m
+providers:
+  p: {type: openai}
+models:
+  m: {provider: p, model: gpt-4o}
+agents:
+  helper: {}
+chats:
+  quick: {}
+`)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diags := Validate(cfg); len(diags) != 0 {
+		t.Errorf("expected agents and chats to fall back to default_model, got %v", diags)
+	}
+}
@@ -45,8 +45,12 @@ func (v *Value) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
+// Location returns the position of the value in its configuration file as file:line:col
 func (v *Value) Location() string {
-	return fmt.Sprintf("file:%v@(%d:%d)", v.file, v.node.Line, v.node.Column)
+	if v.node == nil {
+		return v.file
+	}
+	return fmt.Sprintf("%s:%d:%d", v.file, v.node.Line, v.node.Column)
 }
 
 func (v *Value) IsMap() bool {
@@ -70,7 +74,7 @@ func (v *Value) Get(key string) *Value {
 		for i := 0; i < len(v.node.Content); i = i + 2 {
 			k := v.node.Content[i].Value
 			if k == key {
-				return &Value{node: v.node.Content[i+1]}
+				return &Value{file: v.file, node: v.node.Content[i+1]}
 			}
 		}
 	}
@@ -83,7 +87,7 @@ func (v *Value) Map() map[string]*Value {
 		for i := 0; i < len(v.node.Content); i = i + 2 {
 			key := v.node.Content[i].Value
 			value := v.node.Content[i+1]
-			values[key] = &Value{node: value}
+			values[key] = &Value{file: v.file, node: value}
 		}
 		return values
 	}
@@ -94,7 +98,7 @@ func (v *Value) Array() []*Value {
 	if v.node.Kind == yaml.SequenceNode {
 		values := make([]*Value, len(v.node.Content))
 		for i, node := range v.node.Content {
-			values[i] = &Value{node: node}
+			values[i] = &Value{file: v.file, node: node}
 		}
 		return values
 	}
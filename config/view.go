@@ -0,0 +1,120 @@
+package config
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RedactedValue replaces secret values in redacted views
+const RedactedValue = "******"
+
+// secretKeyParts marks keys whose values are secrets
+var secretKeyParts = []string{"api_key", "apikey", "secret", "token", "password", "authorization", "credential", "private_key"}
+
+// MergedNode returns the merged YAML document of all loaded files, with references already expanded.
+// Sections are merged per key in the same way as Merge; the returned node is a copy.
+func (c *Config) MergedNode() *yaml.Node {
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, l := range c.layers {
+		root, _ := locate(l.root, nil)
+		if root == nil || root.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			key, value := root.Content[i], root.Content[i+1]
+			if key.Value == "include" {
+				continue
+			}
+			existing := lookupNode(merged, key.Value)
+			if existing != nil && existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode {
+				for j := 0; j+1 < len(value.Content); j += 2 {
+					setMapping(existing, copyNode(value.Content[j]), copyNode(value.Content[j+1]))
+				}
+				continue
+			}
+			setMapping(merged, copyNode(key), copyNode(value))
+		}
+	}
+	return merged
+}
+
+// Redact replaces the values of secret-looking keys in the node tree in place
+func Redact(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			Redact(child)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if IsSecretKey(key.Value) {
+				redactAll(value)
+				continue
+			}
+			Redact(value)
+		}
+	}
+}
+
+// redactAll replaces every non-empty scalar in the node tree, used below secret keys such as api_keys
+func redactAll(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode {
+		if node.Value != "" {
+			node.Value = RedactedValue
+			node.Tag = "!!str"
+			node.Style = 0
+		}
+		return
+	}
+	if node.Kind == yaml.MappingNode {
+		for i := 1; i < len(node.Content); i += 2 {
+			redactAll(node.Content[i])
+		}
+		return
+	}
+	for _, child := range node.Content {
+		redactAll(child)
+	}
+}
+
+// IsSecretKey reports whether a configuration key usually holds a secret
+func IsSecretKey(key string) bool {
+	key = strings.ToLower(strings.ReplaceAll(key, "-", "_"))
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// setMapping sets a key of a mapping node, replacing the existing value
+func setMapping(node, key, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key.Value {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, key, value)
+}
+
+// copyNode deep copies a node tree, so that views can be modified without touching the loaded files
+func copyNode(node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	copied := *node
+	if node.Alias != nil {
+		copied.Alias = copyNode(node.Alias)
+	}
+	if len(node.Content) > 0 {
+		copied.Content = make([]*yaml.Node, len(node.Content))
+		for i, child := range node.Content {
+			copied.Content[i] = copyNode(child)
+		}
+	}
+	return &copied
+}
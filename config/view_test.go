@@ -0,0 +1,120 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRedact(t *testing.T) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(`providers:
+  p:
+    type: openai
+    api_key: sk-1
+    api_keys: [sk-2, sk-3]
+    headers:
+      Authorization: Bearer sk-4
+      X-Title: eino-cli
+    empty_token: ""
+mcp_servers:
+  github:
+    env:
+      GITHUB_TOKEN: ghp-5
+      LOG_LEVEL: debug
+tools:
+  api:
+    config:
+      auth:
+        type: oauth2
+        client-secret: cs-6
+        credentials: {user: u, pass: p-7}
+`), &doc); err != nil {
+		t.Fatal(err)
+	}
+	Redact(&doc)
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range []string{"sk-1", "sk-2", "sk-3", "sk-4", "ghp-5", "cs-6", "u,", "p-7"} {
+		if strings.Contains(string(out), secret) {
+			t.Errorf("expected %s to be redacted:\n%s", secret, out)
+		}
+	}
+	for _, kept := range []string{"type: openai", "X-Title: eino-cli", "LOG_LEVEL: debug", "type: oauth2", `empty_token: ""`, "{user: '" + RedactedValue + "'"} {
+		if !strings.Contains(string(out), kept) {
+			t.Errorf("expected %q to be kept:\n%s", kept, out)
+		}
+	}
+}
+
+func TestIsSecretKey(t *testing.T) {
+	tests := map[string]bool{
+		"api_key":       true,
+		"API-Key":       true,
+		"apikey":        true,
+		"secret_key":    true,
+		"access_token":  true,
+		"Authorization": true,
+		"password":      true,
+		"private_key":   true,
+		"base_url":      false,
+		"model":         false,
+		"access_key":    false,
+	}
+	for key, want := range tests {
+		if got := IsSecretKey(key); got != want {
+			t.Errorf("IsSecretKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestMergedNode(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "shared.yml", `models:
+  a: {provider: p, model: from-include}
+  b: {provider: p, model: b}
+`)
+	path := writeFile(t, dir, "config.yml", `include: [shared.yml]
+providers:
+  p: {type: openai, api_key: sk-1}
+models:
+  a: {provider: p, model: from-main}
+`)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged := cfg.MergedNode()
+	for _, tt := range []struct {
+		path string
+		want string
+	}{
+		{"models.a.model", "from-main"},
+		{"models.b.model", "b"},
+		{"providers.p.api_key", "sk-1"},
+	} {
+		keys, _ := ParsePath(tt.path)
+		node, err := LookupPath(merged, keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if node.Value != tt.want {
+			t.Errorf("expected %s to be %s, got %s", tt.path, tt.want, node.Value)
+		}
+	}
+	if _, err := LookupPath(merged, []string{"include"}); err == nil {
+		t.Error("expected include to be left out of the merged view")
+	}
+
+	// Redacting the view leaves the loaded files untouched
+	Redact(merged)
+	node, _ := LookupPath(cfg.MergedNode(), []string{"providers", "p", "api_key"})
+	if node.Value != "sk-1" {
+		t.Errorf("expected the loaded file to keep its secret, got %s", node.Value)
+	}
+}
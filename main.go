@@ -7,9 +7,13 @@ import (
 
 	"github.com/tk103331/eino-cli/cmd"
 	"github.com/tk103331/eino-cli/logger"
+	"github.com/tk103331/eino-cli/tools/custom"
 )
 
 func main() {
+	// Act as the sandbox helper when an exec tool re-executed the binary
+	custom.RunSandboxHelper()
+
 	// Initialize logging
 	if err := logger.Init(); err != nil {
 		// If logging fails, still run the app but log to stderr
@@ -26,6 +30,7 @@ func main() {
 	go func() {
 		sig := <-sigChan
 		logger.Info("MAIN", "Received signal, shutting down: "+sig.String())
+		cmd.Shutdown()
 		logger.Close()
 		os.Exit(0)
 	}()
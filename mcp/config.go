@@ -18,7 +18,7 @@ func ValidateConfig(cfg *config.Config) error {
 
 	// Validate MCP server configurations
 	for serverName, serverConfig := range cfg.MCPServers {
-		if err := validateServerConfig(serverName, serverConfig); err != nil {
+		if err := ValidateServerConfig(serverName, serverConfig); err != nil {
 			return err
 		}
 	}
@@ -36,14 +36,22 @@ func ValidateConfig(cfg *config.Config) error {
 	return nil
 }
 
-// validateServerConfig validates a single MCP server configuration
-func validateServerConfig(serverName string, serverConfig config.MCPServer) error {
+// ValidateServerConfig validates a single MCP server configuration
+func ValidateServerConfig(serverName string, serverConfig config.MCPServer) error {
 	// Validate server name
 	if strings.TrimSpace(serverName) == "" {
 		return NewMCPError("validate", serverName, "",
 			fmt.Errorf("MCP server name cannot be empty"))
 	}
 
+	// Validate server type
+	switch strings.ToLower(serverConfig.Type) {
+	case "stdio", "sse", "streamable-http", "http":
+	default:
+		return NewMCPError("validate", serverName, "",
+			fmt.Errorf("unsupported MCP server type: %s", serverConfig.Type))
+	}
+
 	// Validate command or URL
 	if serverConfig.Cmd == "" && serverConfig.URL == "" {
 		return NewMCPError("validate", serverName, "",
@@ -7,6 +7,7 @@ import (
 
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/tk103331/eino-cli/config"
+	"github.com/tk103331/eino-cli/tools"
 )
 
 // Manager MCP manager, responsible for managing all MCP clients and tools
@@ -63,16 +64,20 @@ func (m *Manager) GetToolsForAgent(agentName string) ([]tool.InvokableTool, erro
 		return []tool.InvokableTool{}, nil
 	}
 
-	// Get tools from specified servers
-	mcpTools := m.client.GetToolsForServers(serverNames)
-
-	// Convert to tool list
-	tools := make([]tool.InvokableTool, 0, len(mcpTools))
-	for _, mcpTool := range mcpTools {
-		tools = append(tools, mcpTool)
+	// Get tools from specified servers, each wrapped with the approval policy of its server
+	var agentTools []tool.InvokableTool
+	for _, serverName := range serverNames {
+		serverCfg := m.config.MCPServers[serverName]
+		for toolName, mcpTool := range m.client.GetToolsForServers([]string{serverName}) {
+			gated, err := tools.WithApproval(toolName, serverCfg.Approval, serverCfg.ApprovalRules, mcpTool)
+			if err != nil {
+				return nil, NewMCPError("get_tools", serverName, toolName, err)
+			}
+			agentTools = append(agentTools, gated)
+		}
 	}
 
-	return tools, nil
+	return agentTools, nil
 }
 
 // GetAllTools gets all MCP tools
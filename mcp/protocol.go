@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/client"
@@ -13,12 +14,12 @@ import (
 
 // createMCPClient creates MCP client based on configuration
 func (c *Client) createMCPClient(ctx context.Context, serverName string, serverConfig config.MCPServer) (*client.Client, error) {
-	switch serverConfig.Type {
-	case "stdio", "STDIO":
+	switch strings.ToLower(serverConfig.Type) {
+	case "stdio":
 		return c.createStdioClient(ctx, serverConfig)
-	case "sse", "SSE":
+	case "sse":
 		return c.createSSEClient(ctx, serverConfig)
-	case "streamable-http", "STREAMABLE-HTTP", "http", "HTTP":
+	case "streamable-http", "http":
 		return c.createStreamableHTTPClient(ctx, serverConfig)
 	default:
 		return nil, fmt.Errorf("unsupported MCP server type: %s", serverConfig.Type)
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino-ext/components/model/ark"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/tk103331/eino-cli/config"
+	arkModel "github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+)
+
+func init() {
+	Register("ark", newArkModel)
+	RegisterStatusCode(arkStatusCode)
+}
+
+// arkStatusCode returns the status code of an Ark runtime error
+func arkStatusCode(err error) int {
+	if code := errorStatus(err, func(e *arkModel.APIError) int { return e.HTTPStatusCode }); code != 0 {
+		return code
+	}
+	return errorStatus(err, func(e *arkModel.RequestError) int { return e.HTTPStatusCode })
+}
+
+// newArkModel creates Ark model
+func newArkModel(ctx context.Context, modelCfg *config.Model, providerCfg *config.Provider) (model.ToolCallingChatModel, error) {
+	httpClient, err := newHTTPClient(providerCfg)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &ark.ChatModelConfig{
+		Model:            modelCfg.Model,
+		BaseURL:          providerCfg.BaseURL,
+		APIKey:           providerCfg.APIKey,
+		AccessKey:        providerCfg.AccessKey,
+		SecretKey:        providerCfg.SecretKey,
+		HTTPClient:       httpClient,
+		MaxTokens:        modelCfg.MaxTokens,
+		Temperature:      float32Ptr(modelCfg.Temperature),
+		TopP:             float32Ptr(modelCfg.TopP),
+		Stop:             modelCfg.Stop,
+		PresencePenalty:  float32Ptr(modelCfg.PresencePenalty),
+		FrequencyPenalty: float32Ptr(modelCfg.FrequencyPenalty),
+	}
+	if modelCfg.ResponseFormat == responseFormatJSONObject {
+		cfg.ResponseFormat = &ark.ResponseFormat{Type: arkModel.ResponseFormatType(responseFormatJSONObject)}
+	}
+	if modelCfg.ReasoningEffort != "" {
+		// Ark only switches thinking on, without an effort level
+		cfg.Thinking = &arkModel.Thinking{Type: arkModel.ThinkingTypeEnabled}
+	}
+	warnIgnored(modelCfg, providerCfg.Type, "top_k", "seed")
+	if err := applyExtra(modelCfg, cfg); err != nil {
+		return nil, err
+	}
+
+	return ark.NewChatModel(ctx, cfg)
+}
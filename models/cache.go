@@ -0,0 +1,411 @@
+package models
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/tk103331/eino-cli/config"
+	"github.com/tk103331/eino-cli/logger"
+)
+
+const (
+	defaultCacheTTL     = 24 * time.Hour
+	defaultCacheMaxSize = 100 // MB
+)
+
+// cacheDisabled is set by --no-cache
+var cacheDisabled bool
+
+// DisableCache turns off the response cache for this process, even if it is enabled in the configuration
+func DisableCache() {
+	cacheDisabled = true
+}
+
+var (
+	// cacheShardPattern matches the subdirectories holding the entries whose hash starts with their name
+	cacheShardPattern = regexp.MustCompile(`^[0-9a-f]{2}$`)
+	// cacheEntryPattern matches entry files, including temporary files left by an interrupted write
+	cacheEntryPattern = regexp.MustCompile(`^[0-9a-f]{64}\.json(\.tmp)?$`)
+)
+
+// CacheDir returns the cache directory of the settings, ~/.eino-cli/cache by default
+func CacheDir(settings *config.CacheSettings) string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	if settings != nil && settings.Dir != "" {
+		if rest, ok := strings.CutPrefix(settings.Dir, "~/"); ok {
+			return filepath.Join(homeDir, rest)
+		}
+		return settings.Dir
+	}
+	return filepath.Join(homeDir, ".eino-cli", "cache")
+}
+
+// ClearCache removes all cached responses. Only cache entries are removed, so other files
+// in a cache directory pointed at the wrong place are left alone.
+func ClearCache(settings *config.CacheSettings) error {
+	dir := CacheDir(settings)
+	var errs []error
+	walkCacheEntries(dir, func(path string, info fs.FileInfo) {
+		if err := os.Remove(path); err != nil {
+			errs = append(errs, err)
+		}
+	})
+	// Directories are only removed once empty
+	if shards, err := os.ReadDir(dir); err == nil {
+		for _, shard := range shards {
+			if shard.IsDir() && cacheShardPattern.MatchString(shard.Name()) {
+				_ = os.Remove(filepath.Join(dir, shard.Name()))
+			}
+		}
+	}
+	_ = os.Remove(dir)
+	return errors.Join(errs...)
+}
+
+// walkCacheEntries calls fn for every entry file in the cache directory
+func walkCacheEntries(dir string, fn func(path string, info fs.FileInfo)) {
+	shards, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, shard := range shards {
+		if !shard.IsDir() || !cacheShardPattern.MatchString(shard.Name()) {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(dir, shard.Name()))
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			if f.IsDir() || !cacheEntryPattern.MatchString(f.Name()) || !strings.HasPrefix(f.Name(), shard.Name()) {
+				continue
+			}
+			info, err := f.Info()
+			if err != nil {
+				continue
+			}
+			fn(filepath.Join(dir, shard.Name(), f.Name()), info)
+		}
+	}
+}
+
+// responseCache stores model responses on disk, one file per request hash
+type responseCache struct {
+	dir     string
+	ttl     time.Duration
+	maxSize int64
+
+	// The cache directory is only scanned when the estimated size exceeds the limit or entries may have expired
+	mu       sync.Mutex
+	size     int64     // Size found by the last prune plus the entries written since
+	prunedAt time.Time // Zero before the first prune
+}
+
+// cacheEntry is a cached response, kept as stream chunks so that streams are replayed chunk by chunk
+type cacheEntry struct {
+	CreatedAt time.Time         `json:"created_at"`
+	Chunks    []*schema.Message `json:"chunks"`
+}
+
+// newResponseCache creates the cache of the settings, or nil when caching is off
+func newResponseCache(settings *config.CacheSettings) (*responseCache, error) {
+	if settings == nil || !settings.Enabled || cacheDisabled {
+		return nil, nil
+	}
+	c := &responseCache{dir: CacheDir(settings), ttl: defaultCacheTTL, maxSize: defaultCacheMaxSize << 20}
+	if settings.TTL != "" {
+		ttl, err := time.ParseDuration(settings.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache ttl %q: %w", settings.TTL, err)
+		}
+		c.ttl = ttl
+	}
+	if settings.MaxSize > 0 {
+		c.maxSize = int64(settings.MaxSize) << 20
+	}
+	return c, nil
+}
+
+// path returns the file of a request hash
+func (c *responseCache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// get returns the cached chunks of a request, expired entries are removed
+func (c *responseCache) get(key string) ([]*schema.Message, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || len(entry.Chunks) == 0 || time.Since(entry.CreatedAt) > c.ttl {
+		_ = os.Remove(c.path(key))
+		return nil, false
+	}
+	return entry.Chunks, true
+}
+
+// put stores the chunks of a response and prunes the cache to its size limit
+func (c *responseCache) put(key string, chunks []*schema.Message) {
+	data, err := json.Marshal(cacheEntry{CreatedAt: time.Now(), Chunks: chunks})
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(c.path(key)), 0755)
+	}
+	if err == nil {
+		tmp := c.path(key) + ".tmp"
+		if err = os.WriteFile(tmp, data, 0600); err == nil {
+			err = os.Rename(tmp, c.path(key))
+		}
+	}
+	if err != nil {
+		logger.Warn("MODEL", fmt.Sprintf("Failed to cache response: %v", err))
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.size += int64(len(data))
+	if c.prunedAt.IsZero() || c.size > c.maxSize || time.Since(c.prunedAt) > c.ttl {
+		c.size = c.prune()
+		c.prunedAt = time.Now()
+	}
+}
+
+// prune removes expired entries, then the oldest entries until the cache fits its size limit,
+// returns the size of the remaining entries
+func (c *responseCache) prune() int64 {
+	type file struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []file
+	var total int64
+	walkCacheEntries(c.dir, func(path string, info fs.FileInfo) {
+		if time.Since(info.ModTime()) > c.ttl {
+			_ = os.Remove(path)
+			return
+		}
+		files = append(files, file{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	})
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		if total <= c.maxSize {
+			break
+		}
+		if os.Remove(f.path) == nil {
+			total -= f.size
+		}
+	}
+	return total
+}
+
+// cachedModel reuses responses of identical requests
+type cachedModel struct {
+	inner model.ToolCallingChatModel
+	cache *responseCache
+	id    any    // Model and provider settings that affect the response
+	model string // Model name reported to callbacks on cache hits
+	tools []*schema.ToolInfo
+}
+
+// newCachedModel wraps a model with the response cache. The whole provider configuration is part of the key,
+// since headers, deployments or the API version can change the response, except the credentials, so that
+// rotating keys does not invalidate the cache.
+func newCachedModel(inner model.ToolCallingChatModel, cache *responseCache, modelCfg config.Model, providerCfg config.Provider) model.ToolCallingChatModel {
+	modelCfg.Pricing, modelCfg.Retry = nil, nil
+	providerCfg.APIKey, providerCfg.APIKeys = "", nil
+	providerCfg.AccessKey, providerCfg.SecretKey = "", ""
+	id := struct {
+		Model    config.Model
+		Provider config.Provider
+	}{modelCfg, providerCfg}
+	return &cachedModel{inner: inner, cache: cache, id: id, model: modelCfg.Model}
+}
+
+// key hashes the model settings, messages, bound tools and call options of a request
+func (m *cachedModel) key(input []*schema.Message, opts []model.Option) (string, error) {
+	options := model.GetCommonOptions(nil, opts...)
+	tools := m.tools
+	if len(options.Tools) > 0 {
+		tools = options.Tools
+	}
+	// Tool parameters are unexported, hash their JSON schema instead
+	type toolKey struct {
+		Name   string
+		Desc   string
+		Params any
+	}
+	toolKeys := make([]toolKey, len(tools))
+	for i, t := range tools {
+		toolKeys[i] = toolKey{Name: t.Name, Desc: t.Desc}
+		if t.ParamsOneOf != nil {
+			params, err := t.ParamsOneOf.ToJSONSchema()
+			if err != nil {
+				return "", err
+			}
+			toolKeys[i].Params = params
+		}
+	}
+	data, err := json.Marshal(struct {
+		ID          any
+		Messages    []*schema.Message
+		Tools       []toolKey
+		Temperature *float32
+		MaxTokens   *int
+		Model       *string
+		TopP        *float32
+		Stop        []string
+		ToolChoice  *schema.ToolChoice
+	}{m.id, input, toolKeys, options.Temperature, options.MaxTokens, options.Model, options.TopP, options.Stop, options.ToolChoice})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Generate implements model.BaseChatModel
+func (m *cachedModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	key, err := m.key(input, opts)
+	if err != nil {
+		return m.inner.Generate(ctx, input, opts...)
+	}
+	if chunks, ok := m.cache.get(key); ok {
+		logger.Debug("MODEL", "Response served from cache")
+		msg, err := concatCached(chunks)
+		if !m.IsCallbacksEnabled() {
+			return msg, err
+		}
+		// The wrapped model is not called, report the hit to the handlers as it would
+		ctx = callbacks.OnStart(ctx, m.callbackInput(input, opts))
+		if err != nil {
+			callbacks.OnError(ctx, err)
+			return nil, err
+		}
+		callbacks.OnEnd(ctx, &model.CallbackOutput{Message: msg, Config: &model.Config{Model: m.model}})
+		return msg, nil
+	}
+	msg, err := m.inner.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	m.cache.put(key, []*schema.Message{msg})
+	return msg, nil
+}
+
+// Stream implements model.BaseChatModel. Cached responses are replayed chunk by chunk,
+// new responses are cached once the stream has been read to the end.
+func (m *cachedModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	key, err := m.key(input, opts)
+	if err != nil {
+		return m.inner.Stream(ctx, input, opts...)
+	}
+	if chunks, ok := m.cache.get(key); ok {
+		logger.Debug("MODEL", "Response served from cache")
+		chunks = withoutUsage(chunks)
+		if !m.IsCallbacksEnabled() {
+			return schema.StreamReaderFromArray(chunks), nil
+		}
+		// The wrapped model is not called, report the replay to the handlers as it would
+		ctx = callbacks.OnStart(ctx, m.callbackInput(input, opts))
+		outputs := make([]*model.CallbackOutput, len(chunks))
+		for i, chunk := range chunks {
+			outputs[i] = &model.CallbackOutput{Message: chunk, Config: &model.Config{Model: m.model}}
+		}
+		_, stream := callbacks.OnEndWithStreamOutput(ctx, schema.StreamReaderFromArray(outputs))
+		return schema.StreamReaderWithConvert(stream, func(output *model.CallbackOutput) (*schema.Message, error) {
+			return output.Message, nil
+		}), nil
+	}
+	stream, err := m.inner.Stream(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	copies := stream.Copy(2)
+	go func() {
+		reader := copies[1]
+		defer reader.Close()
+		var chunks []*schema.Message
+		for {
+			chunk, err := reader.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				// Incomplete responses are not cached
+				return
+			}
+			chunks = append(chunks, chunk)
+		}
+		if len(chunks) > 0 {
+			m.cache.put(key, chunks)
+		}
+	}()
+	return copies[0], nil
+}
+
+// WithTools implements model.ToolCallingChatModel, the tools become part of the cache key
+func (m *cachedModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	inner, err := m.inner.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &cachedModel{inner: inner, cache: m.cache, id: m.id, model: m.model, tools: tools}, nil
+}
+
+// callbackInput describes a request served from the cache to callback handlers
+func (m *cachedModel) callbackInput(input []*schema.Message, opts []model.Option) *model.CallbackInput {
+	options := model.GetCommonOptions(nil, opts...)
+	tools := m.tools
+	if len(options.Tools) > 0 {
+		tools = options.Tools
+	}
+	return &model.CallbackInput{Messages: input, Tools: tools, Config: &model.Config{Model: m.model}}
+}
+
+// IsCallbacksEnabled reports whether the wrapped model handles callbacks itself, cache hits then call them here
+func (m *cachedModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(m.inner)
+}
+
+// concatCached joins cached chunks into one message
+func concatCached(chunks []*schema.Message) (*schema.Message, error) {
+	chunks = withoutUsage(chunks)
+	if len(chunks) == 1 {
+		return chunks[0], nil
+	}
+	return schema.ConcatMessages(chunks)
+}
+
+// withoutUsage drops the token usage of cached chunks, a cached response costs no tokens
+func withoutUsage(chunks []*schema.Message) []*schema.Message {
+	for _, chunk := range chunks {
+		if chunk.ResponseMeta != nil {
+			chunk.ResponseMeta.Usage = nil
+		}
+	}
+	return chunks
+}
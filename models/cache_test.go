@@ -0,0 +1,244 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/tk103331/eino-cli/config"
+)
+
+// cacheKey returns a valid entry hash starting with the given character
+func cacheKey(c byte) string {
+	return strings.Repeat(string(c), 64)
+}
+
+func TestCacheDir(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip(err)
+	}
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{"", filepath.Join(home, ".eino-cli", "cache")},
+		{"~/cache", filepath.Join(home, "cache")},
+		{"/tmp/cache", "/tmp/cache"},
+		{"cache", "cache"},
+	}
+	for _, tt := range tests {
+		if got := CacheDir(&config.CacheSettings{Dir: tt.dir}); got != tt.want {
+			t.Errorf("CacheDir(%q) = %s, want %s", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestClearCacheKeepsOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	c := &responseCache{dir: dir, ttl: defaultCacheTTL, maxSize: defaultCacheMaxSize << 20}
+	c.put(cacheKey('a'), []*schema.Message{schema.AssistantMessage("cached", nil)})
+	c.put(cacheKey('b'), []*schema.Message{schema.AssistantMessage("cached", nil)})
+
+	others := []string{"notes.txt", filepath.Join("aa", "notes.json"), filepath.Join("src", "main.go")}
+	for _, name := range others {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("keep"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := ClearCache(&config.CacheSettings{Dir: dir}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.get(cacheKey('a')); ok {
+		t.Error("expected the cached response to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "bb")); !os.IsNotExist(err) {
+		t.Error("expected the emptied shard directory to be removed")
+	}
+	for _, name := range others {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s to be kept: %v", name, err)
+		}
+	}
+
+	if err := ClearCache(&config.CacheSettings{Dir: filepath.Join(dir, "missing")}); err != nil {
+		t.Errorf("expected clearing a missing cache to succeed: %v", err)
+	}
+}
+
+func TestResponseCachePrune(t *testing.T) {
+	dir := t.TempDir()
+	chunks := []*schema.Message{schema.AssistantMessage(strings.Repeat("x", 1000), nil)}
+	c := &responseCache{dir: dir, ttl: defaultCacheTTL, maxSize: 2500}
+
+	keys := []string{cacheKey('a'), cacheKey('b'), cacheKey('c')}
+	for _, key := range keys {
+		c.put(key, chunks)
+	}
+	// The third entry exceeds the limit, so the oldest one is removed
+	if _, ok := c.get(keys[0]); ok {
+		t.Error("expected the oldest entry to be pruned")
+	}
+	for _, key := range keys[1:] {
+		if _, ok := c.get(key); !ok {
+			t.Errorf("expected entry %s to be kept", key[:2])
+		}
+	}
+	if c.size <= 0 || c.size > c.maxSize {
+		t.Errorf("expected the size estimate to be within the limit, got %d", c.size)
+	}
+}
+
+func TestCachedModelCallbacks(t *testing.T) {
+	for _, stream := range []bool{false, true} {
+		name := "generate"
+		if stream {
+			name = "stream"
+		}
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			cassette := filepath.Join(dir, "cassette.json")
+			// Only one interaction, the second call must be served from the cache
+			interactions := []Interaction{{Response: &MockMessage{Content: "hello there", Chunks: []string{"hello ", "there"}}}}
+			if err := (&Cassette{Interactions: interactions}).Save(cassette); err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			modelCfg := config.Model{Provider: "fake", Model: "scripted"}
+			providerCfg := config.Provider{Type: "mock", Cassette: cassette}
+			inner, err := newMockModel(ctx, &modelCfg, &providerCfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cache := &responseCache{dir: filepath.Join(dir, "cache"), ttl: defaultCacheTTL, maxSize: defaultCacheMaxSize << 20}
+			m := newCachedModel(inner, cache, modelCfg, providerCfg)
+
+			var (
+				mu     sync.Mutex
+				starts int
+				ends   []string
+			)
+			handler := callbacks.NewHandlerBuilder().
+				OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+					mu.Lock()
+					starts++
+					mu.Unlock()
+					return ctx
+				}).
+				OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+					mu.Lock()
+					ends = append(ends, model.ConvCallbackOutput(output).Message.Content)
+					mu.Unlock()
+					return ctx
+				}).
+				OnEndWithStreamOutputFn(func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+					defer output.Close()
+					var content strings.Builder
+					for {
+						chunk, err := output.Recv()
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						if err != nil {
+							t.Error(err)
+							return ctx
+						}
+						content.WriteString(model.ConvCallbackOutput(chunk).Message.Content)
+					}
+					mu.Lock()
+					ends = append(ends, content.String())
+					mu.Unlock()
+					return ctx
+				}).
+				Build()
+			ctx = callbacks.InitCallbacks(ctx, &callbacks.RunInfo{Name: "test"}, handler)
+
+			input := []*schema.Message{schema.UserMessage("hello")}
+			for i := 0; i < 2; i++ {
+				var msg *schema.Message
+				if stream {
+					sr, err := m.Stream(ctx, input)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if msg, err = schema.ConcatMessageStream(sr); err != nil {
+						t.Fatal(err)
+					}
+					// The stream is cached once it has been read to the end
+					for j := 0; j < 100; j++ {
+						if _, ok := cache.get(mustKey(t, m, input)); ok {
+							break
+						}
+						time.Sleep(10 * time.Millisecond)
+					}
+				} else if msg, err = m.Generate(ctx, input); err != nil {
+					t.Fatal(err)
+				}
+				if msg.Content != "hello there" {
+					t.Fatalf("expected reply %q, got %q", "hello there", msg.Content)
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if starts != 2 || strings.Join(ends, "|") != "hello there|hello there" {
+				t.Fatalf("expected the handlers to see the call and the cache hit, got %d starts and ends %q", starts, ends)
+			}
+		})
+	}
+}
+
+// mustKey returns the cache key of a request to a cached model
+func mustKey(t *testing.T, m model.ToolCallingChatModel, input []*schema.Message) string {
+	t.Helper()
+	key, err := m.(*cachedModel).key(input, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestCachedModelKey(t *testing.T) {
+	base := config.Provider{Type: "azure", BaseURL: "https://a.example.test", APIKey: "sk-a", APIVersion: "2024-06-01",
+		Headers: map[string]string{"X-Region": "eu"}, Deployments: map[string]string{"gpt": "gpt-eu"}}
+	tests := []struct {
+		name     string
+		change   func(p *config.Provider)
+		wantSame bool
+	}{
+		{"api key", func(p *config.Provider) { p.APIKey = "sk-b" }, true},
+		{"api keys", func(p *config.Provider) { p.APIKeys = []config.APIKey{{Key: "sk-c"}} }, true},
+		{"access and secret key", func(p *config.Provider) { p.AccessKey, p.SecretKey = "ak", "sk" }, true},
+		{"headers", func(p *config.Provider) { p.Headers = map[string]string{"X-Region": "us"} }, false},
+		{"api version", func(p *config.Provider) { p.APIVersion = "2025-01-01" }, false},
+		{"deployments", func(p *config.Provider) { p.Deployments = map[string]string{"gpt": "gpt-us"} }, false},
+		{"base url", func(p *config.Provider) { p.BaseURL = "https://b.example.test" }, false},
+	}
+	modelCfg := config.Model{Provider: "azure", Model: "gpt"}
+	input := []*schema.Message{schema.UserMessage("hello")}
+	want := mustKey(t, newCachedModel(nil, nil, modelCfg, base), input)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			providerCfg := base
+			tt.change(&providerCfg)
+			got := mustKey(t, newCachedModel(nil, nil, modelCfg, providerCfg), input)
+			if (got == want) != tt.wantSame {
+				t.Errorf("expected the same key: %v, got %s and %s", tt.wantSame, want, got)
+			}
+		})
+	}
+}
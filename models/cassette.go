@@ -0,0 +1,135 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// Cassette holds scripted or recorded model exchanges, replayed by the mock provider
+type Cassette struct {
+	Interactions []Interaction `yaml:"interactions" json:"interactions"`
+}
+
+// Interaction is one model call of a cassette
+type Interaction struct {
+	Model    string       `yaml:"model,omitempty" json:"model,omitempty"`   // Only replayed for this model, matched against the model setting
+	Match    string       `yaml:"match,omitempty" json:"match,omitempty"`   // Regular expression the last input message must match
+	Input    string       `yaml:"input,omitempty" json:"input,omitempty"`   // Last input message, recorded for reference
+	Stream   bool         `yaml:"stream,omitempty" json:"stream,omitempty"` // Recorded from a streaming call
+	Response *MockMessage `yaml:"response,omitempty" json:"response,omitempty"`
+	Error    string       `yaml:"error,omitempty" json:"error,omitempty"` // Returned as an error instead of a response
+}
+
+// MockMessage is a model response of a cassette
+type MockMessage struct {
+	Content          string         `yaml:"content,omitempty" json:"content,omitempty"`
+	ReasoningContent string         `yaml:"reasoning_content,omitempty" json:"reasoning_content,omitempty"`
+	ToolCalls        []MockToolCall `yaml:"tool_calls,omitempty" json:"tool_calls,omitempty"`
+	Chunks           []string       `yaml:"chunks,omitempty" json:"chunks,omitempty"` // Content split into stream chunks, default is a single chunk
+	PromptTokens     int            `yaml:"prompt_tokens,omitempty" json:"prompt_tokens,omitempty"`
+	CompletionTokens int            `yaml:"completion_tokens,omitempty" json:"completion_tokens,omitempty"`
+}
+
+// MockToolCall is a tool call of a cassette response
+type MockToolCall struct {
+	ID        string `yaml:"id,omitempty" json:"id,omitempty"`
+	Name      string `yaml:"name" json:"name"`
+	Arguments string `yaml:"arguments,omitempty" json:"arguments,omitempty"` // JSON arguments, default {}
+}
+
+// LoadCassette reads a YAML or JSON cassette
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var cassette Cassette
+	// YAML is a superset of JSON
+	if err := yaml.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette as JSON or YAML depending on the file extension
+func (c *Cassette) Save(path string) error {
+	var data []byte
+	var err error
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yml" || ext == ".yaml" {
+		data, err = yaml.Marshal(c)
+	} else {
+		data, err = json.MarshalIndent(c, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// toMessage converts a cassette response to a schema message
+func (m *MockMessage) toMessage(content string) *schema.Message {
+	msg := &schema.Message{
+		Role:             schema.Assistant,
+		Content:          content,
+		ReasoningContent: m.ReasoningContent,
+	}
+	for i, call := range m.ToolCalls {
+		id := call.ID
+		if id == "" {
+			id = fmt.Sprintf("call_%d", i+1)
+		}
+		args := call.Arguments
+		if args == "" {
+			args = "{}"
+		}
+		index := i
+		msg.ToolCalls = append(msg.ToolCalls, schema.ToolCall{
+			Index:    &index,
+			ID:       id,
+			Type:     "function",
+			Function: schema.FunctionCall{Name: call.Name, Arguments: args},
+		})
+	}
+	if m.PromptTokens > 0 || m.CompletionTokens > 0 {
+		msg.ResponseMeta = &schema.ResponseMeta{Usage: &schema.TokenUsage{
+			PromptTokens:     m.PromptTokens,
+			CompletionTokens: m.CompletionTokens,
+			TotalTokens:      m.PromptTokens + m.CompletionTokens,
+		}}
+	}
+	return msg
+}
+
+// fromMessage converts a schema message to a cassette response
+func fromMessage(msg *schema.Message) *MockMessage {
+	m := &MockMessage{Content: msg.Content, ReasoningContent: msg.ReasoningContent}
+	for _, call := range msg.ToolCalls {
+		m.ToolCalls = append(m.ToolCalls, MockToolCall{ID: call.ID, Name: call.Function.Name, Arguments: call.Function.Arguments})
+	}
+	if msg.ResponseMeta != nil && msg.ResponseMeta.Usage != nil {
+		m.PromptTokens = msg.ResponseMeta.Usage.PromptTokens
+		m.CompletionTokens = msg.ResponseMeta.Usage.CompletionTokens
+	}
+	return m
+}
+
+// lastContent returns the content of the last input message, used to match interactions
+func lastContent(input []*schema.Message) string {
+	if len(input) == 0 {
+		return ""
+	}
+	return input[len(input)-1].Content
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"context"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/cloudwego/eino-ext/components/model/claude"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/tk103331/eino-cli/config"
+)
+
+func init() {
+	Register("claude", newClaudeModel)
+	RegisterStatusCode(func(err error) int {
+		return errorStatus(err, func(e *anthropic.Error) int { return e.StatusCode })
+	})
+}
+
+// newClaudeModel creates Claude model
+func newClaudeModel(ctx context.Context, modelCfg *config.Model, providerCfg *config.Provider) (model.ToolCallingChatModel, error) {
+	httpClient, err := newHTTPClient(providerCfg)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &claude.Config{
+		Model:         modelCfg.Model,
+		APIKey:        providerCfg.APIKey,
+		HTTPClient:    httpClient,
+		MaxTokens:     deref(modelCfg.MaxTokens),
+		Temperature:   float32Ptr(modelCfg.Temperature),
+		TopP:          float32Ptr(modelCfg.TopP),
+		TopK:          int32Ptr(modelCfg.TopK),
+		StopSequences: modelCfg.Stop,
+	}
+	if providerCfg.BaseURL != "" {
+		cfg.BaseURL = &providerCfg.BaseURL
+	}
+	if budget, ok := thinkingBudgets[modelCfg.ReasoningEffort]; ok {
+		cfg.Thinking = &claude.Thinking{Enable: true, BudgetTokens: budget}
+	}
+	warnIgnored(modelCfg, providerCfg.Type, "seed", "presence_penalty", "frequency_penalty", "response_format")
+	if err := applyExtra(modelCfg, cfg); err != nil {
+		return nil, err
+	}
+
+	return claude.NewChatModel(ctx, cfg)
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino-ext/components/model/deepseek"
+	"github.com/cloudwego/eino/components/model"
+	deepseekapi "github.com/cohesion-org/deepseek-go"
+	"github.com/tk103331/eino-cli/config"
+)
+
+func init() {
+	Register("deepseek", newDeepSeekModel)
+	RegisterStatusCode(func(err error) int {
+		return errorStatus(err, func(e *deepseekapi.APIError) int { return e.StatusCode })
+	})
+}
+
+// newDeepSeekModel creates DeepSeek model
+func newDeepSeekModel(ctx context.Context, modelCfg *config.Model, providerCfg *config.Provider) (model.ToolCallingChatModel, error) {
+	httpClient, err := newHTTPClient(providerCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// DeepSeek settings are plain values, zero means the API default
+	cfg := &deepseek.ChatModelConfig{
+		Model:            modelCfg.Model,
+		BaseURL:          providerCfg.BaseURL,
+		APIKey:           providerCfg.APIKey,
+		HTTPClient:       httpClient,
+		MaxTokens:        deref(modelCfg.MaxTokens),
+		Temperature:      deref(float32Ptr(modelCfg.Temperature)),
+		TopP:             deref(float32Ptr(modelCfg.TopP)),
+		Stop:             modelCfg.Stop,
+		PresencePenalty:  deref(float32Ptr(modelCfg.PresencePenalty)),
+		FrequencyPenalty: deref(float32Ptr(modelCfg.FrequencyPenalty)),
+	}
+	if modelCfg.ResponseFormat == responseFormatJSONObject {
+		cfg.ResponseFormatType = deepseek.ResponseFormatTypeJSONObject
+	}
+	warnIgnored(modelCfg, providerCfg.Type, "top_k", "seed", "reasoning_effort")
+	if err := applyExtra(modelCfg, cfg); err != nil {
+		return nil, err
+	}
+
+	return deepseek.NewChatModel(ctx, cfg)
+}
@@ -18,39 +18,122 @@ func NewFactory(cfg *config.Config) *Factory {
 	return &Factory{cfg: cfg}
 }
 
-// CreateChatModel creates corresponding ChatModel based on model name
+// CreateChatModel creates corresponding ChatModel based on model name, an empty name uses default_model.
+// Models with a retry policy or fallback models are wrapped so that failed calls are retried and fall through the chain.
 func (f *Factory) CreateChatModel(ctx context.Context, modelName string) (model.ToolCallingChatModel, error) {
-	// Get model configuration
-	modelCfg, ok := f.cfg.Models[modelName]
+	// Resolve model name
+	modelName, err := f.cfg.ResolveModel(modelName)
+	if err != nil {
+		return nil, err
+	}
+	m, err := f.createChain(ctx, modelName)
+	if err != nil {
+		return nil, err
+	}
+	modelCfg := f.cfg.Models[modelName]
+	providerCfg := f.cfg.Providers[modelCfg.Provider]
+	cache, err := newResponseCache(f.cfg.Settings.Cache)
+	if err != nil {
+		return nil, err
+	}
+	if t, _ := Lookup(providerCfg.Type); cache != nil && !t.Uncached {
+		m = newCachedModel(m, cache, modelCfg, providerCfg)
+	}
+	if r := currentRecorder(); r != nil {
+		return newRecordingModel(modelCfg.Model, m, r), nil
+	}
+	return m, nil
+}
+
+// CreateProviderModel creates the bare provider model of a model name, an empty name uses default_model.
+// Retry policy, fallback models, response cache and recording are left out so that probes measure the provider itself;
+// providers with api_keys use their first key.
+func (f *Factory) CreateProviderModel(ctx context.Context, modelName string) (model.ToolCallingChatModel, error) {
+	modelName, err := f.cfg.ResolveModel(modelName)
+	if err != nil {
+		return nil, err
+	}
+	modelCfg := f.cfg.Models[modelName]
+	providerCfg, ok := f.cfg.Providers[modelCfg.Provider]
 	if !ok {
-		return nil, fmt.Errorf("model configuration does not exist: %s", modelName)
+		return nil, fmt.Errorf("provider configuration does not exist: %s", modelCfg.Provider)
+	}
+	if len(providerCfg.APIKeys) > 0 {
+		providerCfg.APIKey = providerCfg.APIKeys[0].Key
+		providerCfg.APIKeys = nil
 	}
+	return f.createProviderModel(ctx, &modelCfg, &providerCfg)
+}
+
+// createChain creates a model with its retry policy and fallback models
+func (f *Factory) createChain(ctx context.Context, modelName string) (model.ToolCallingChatModel, error) {
+	modelCfg := f.cfg.Models[modelName]
 
+	primary, err := f.createCandidate(ctx, modelName)
+	if err != nil {
+		return nil, err
+	}
+	if modelCfg.Retry == nil && len(modelCfg.Fallback) == 0 {
+		return primary.model, nil
+	}
+
+	// Fallback models use their own retry policy, but not their own fallbacks
+	candidates := []candidate{primary}
+	for _, name := range modelCfg.Fallback {
+		if name == modelName {
+			continue
+		}
+		if _, ok := f.cfg.Models[name]; !ok {
+			return nil, fmt.Errorf("fallback model configuration does not exist: %s", name)
+		}
+		c, err := f.createCandidate(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create fallback model %s: %w", name, err)
+		}
+		candidates = append(candidates, c)
+	}
+	return newResilientModel(candidates), nil
+}
+
+// createCandidate creates a single model with its retry policy
+func (f *Factory) createCandidate(ctx context.Context, modelName string) (candidate, error) {
+	modelCfg := f.cfg.Models[modelName]
+	policy, err := newRetryPolicy(modelCfg.Retry)
+	if err != nil {
+		return candidate{}, fmt.Errorf("model %s: %w", modelName, err)
+	}
+	m, err := f.createModel(ctx, &modelCfg)
+	if err != nil {
+		return candidate{}, err
+	}
+	return candidate{name: modelName, model: m, policy: policy}, nil
+}
+
+// createModel creates the provider model of a model configuration
+func (f *Factory) createModel(ctx context.Context, modelCfg *config.Model) (model.ToolCallingChatModel, error) {
 	// Get provider configuration
 	providerCfg, ok := f.cfg.Providers[modelCfg.Provider]
 	if !ok {
 		return nil, fmt.Errorf("provider configuration does not exist: %s", modelCfg.Provider)
 	}
 
-	// Create corresponding model based on provider type
-	switch providerCfg.Type {
-	case "openai":
-		return f.createOpenAIModel(ctx, &modelCfg, &providerCfg)
-	case "claude":
-		return f.createClaudeModel(ctx, &modelCfg, &providerCfg)
-	case "gemini":
-		return f.createGeminiModel(ctx, &modelCfg, &providerCfg)
-	case "qwen":
-		return f.createQwenModel(ctx, &modelCfg, &providerCfg)
-	case "qianfan":
-		return f.createQianfanModel(ctx, &modelCfg, &providerCfg)
-	case "ark":
-		return f.createArkModel(ctx, &modelCfg, &providerCfg)
-	case "deepseek":
-		return f.createDeepSeekModel(ctx, &modelCfg, &providerCfg)
-	case "ollama":
-		return f.createOllamaModel(ctx, &modelCfg, &providerCfg)
-	default:
+	// Providers with several API keys get one model per key
+	if len(providerCfg.APIKeys) > 0 {
+		return newKeyPool(modelCfg.Provider, &providerCfg, func(keyCfg *config.Provider) (model.ToolCallingChatModel, error) {
+			return f.createProviderModel(ctx, modelCfg, keyCfg)
+		})
+	}
+	return f.createProviderModel(ctx, modelCfg, &providerCfg)
+}
+
+// createProviderModel creates a model with the constructor registered for the provider type
+func (f *Factory) createProviderModel(ctx context.Context, modelCfg *config.Model, providerCfg *config.Provider) (model.ToolCallingChatModel, error) {
+	t, ok := Lookup(providerCfg.Type)
+	if !ok {
 		return nil, fmt.Errorf("unsupported provider type: %s", providerCfg.Type)
 	}
+	// Constructors compare the type in its registered form
+	typedCfg := *providerCfg
+	typedCfg.Type = t.Name
+	return t.New(ctx, modelCfg, &typedCfg)
 }
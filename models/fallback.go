@@ -0,0 +1,151 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/tk103331/eino-cli/logger"
+)
+
+// candidate is one model of a fallback chain with its own retry policy
+type candidate struct {
+	name   string
+	model  model.ToolCallingChatModel
+	policy *retryPolicy
+}
+
+// resilientModel retries failed calls according to each model's retry policy,
+// then falls through to the next model of the chain
+type resilientModel struct {
+	candidates []candidate
+}
+
+// newResilientModel wraps a fallback chain, the first candidate is the primary model
+func newResilientModel(candidates []candidate) model.ToolCallingChatModel {
+	return &resilientModel{candidates: candidates}
+}
+
+// Generate implements model.BaseChatModel
+func (m *resilientModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	var errs []error
+	for _, c := range m.candidates {
+		for attempt := 1; ; attempt++ {
+			msg, err := c.model.Generate(ctx, input, opts...)
+			if err == nil {
+				return msg, nil
+			}
+			if !m.retry(ctx, c, attempt, err) {
+				errs = append(errs, fmt.Errorf("model %s: %w", c.name, err))
+				break
+			}
+		}
+		if !m.fallback(ctx, c, errs[len(errs)-1]) {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// Stream implements model.BaseChatModel. A stream is retried when it fails before its first chunk;
+// errors after content has been streamed are returned to the caller as is.
+func (m *resilientModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	var errs []error
+	for _, c := range m.candidates {
+		for attempt := 1; ; attempt++ {
+			stream, err := startStream(ctx, c.model, input, opts...)
+			if err == nil {
+				return stream, nil
+			}
+			if !m.retry(ctx, c, attempt, err) {
+				errs = append(errs, fmt.Errorf("model %s: %w", c.name, err))
+				break
+			}
+		}
+		if !m.fallback(ctx, c, errs[len(errs)-1]) {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// WithTools implements model.ToolCallingChatModel, binding the tools to every model of the chain
+func (m *resilientModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	candidates := make([]candidate, len(m.candidates))
+	for i, c := range m.candidates {
+		withTools, err := c.model.WithTools(tools)
+		if err != nil {
+			return nil, fmt.Errorf("model %s: %w", c.name, err)
+		}
+		candidates[i] = candidate{name: c.name, model: withTools, policy: c.policy}
+	}
+	return &resilientModel{candidates: candidates}, nil
+}
+
+// IsCallbacksEnabled reports that callbacks are handled by the wrapped models, so that every attempt is traced
+func (m *resilientModel) IsCallbacksEnabled() bool {
+	return true
+}
+
+// retry decides whether to retry after a failed attempt and waits for the backoff
+func (m *resilientModel) retry(ctx context.Context, c candidate, attempt int, err error) bool {
+	if attempt >= c.policy.maxAttempts || !c.policy.shouldRetry(ctx, err) {
+		logger.Warn("MODEL", fmt.Sprintf("Model %s failed after %d attempt(s): %v", c.name, attempt, err))
+		return false
+	}
+	logger.Warn("MODEL", fmt.Sprintf("Model %s attempt %d failed, retrying: %v", c.name, attempt, err))
+	return c.policy.wait(ctx, attempt) == nil
+}
+
+// fallback decides whether the next model of the chain is tried after a model failed.
+// Client errors such as a bad request or rejected credentials end the chain, another model would not fix the request.
+func (m *resilientModel) fallback(ctx context.Context, c candidate, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if code := statusCode(err); isClientError(code) {
+		logger.Warn("MODEL", fmt.Sprintf("Model %s rejected the request with status %d, not trying fallback models", c.name, code))
+		return false
+	}
+	return true
+}
+
+// startStream starts a stream and waits for its first chunk, so that failures before any content can be retried
+func startStream(ctx context.Context, m model.BaseChatModel, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	stream, err := m.Stream(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	first, err := stream.Recv()
+	if err != nil && !errors.Is(err, io.EOF) {
+		stream.Close()
+		return nil, err
+	}
+	if errors.Is(err, io.EOF) {
+		stream.Close()
+		return schema.StreamReaderFromArray([]*schema.Message{}), nil
+	}
+
+	// Replay the first chunk, then forward the rest of the stream
+	reader, writer := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer stream.Close()
+		defer writer.Close()
+		if closed := writer.Send(first, nil); closed {
+			return
+		}
+		for {
+			chunk, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if closed := writer.Send(chunk, err); closed || err != nil {
+				return
+			}
+		}
+	}()
+	return reader, nil
+}
@@ -0,0 +1,173 @@
+package models
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/tk103331/eino-cli/config"
+)
+
+// newChainConfig configures a primary model retried once and falling back to a backup model,
+// both served by the mock provider from a cassette with the given interactions
+func newChainConfig(t *testing.T, interactions []Interaction) *config.Config {
+	t.Helper()
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+	if err := (&Cassette{Interactions: interactions}).Save(cassette); err != nil {
+		t.Fatal(err)
+	}
+	return &config.Config{
+		Providers: map[string]config.Provider{
+			"fake": {Type: "mock", Cassette: cassette},
+		},
+		Models: map[string]config.Model{
+			"primary": {
+				Provider: "fake",
+				Model:    "primary",
+				Retry:    &config.RetryPolicy{MaxAttempts: 2, Backoff: "1ms"},
+				Fallback: []string{"backup"},
+			},
+			"backup": {Provider: "fake", Model: "backup"},
+		},
+	}
+}
+
+func failure(model, err string) Interaction {
+	return Interaction{Model: model, Error: err}
+}
+
+func reply(model, content string) Interaction {
+	return Interaction{Model: model, Response: &MockMessage{Content: content}}
+}
+
+func TestResilientModel(t *testing.T) {
+	tests := []struct {
+		name         string
+		interactions []Interaction
+		want         string // Expected reply content
+		wantErr      string // Expected error substring, the chain must not reach the backup model
+	}{
+		{
+			name:         "primary answers",
+			interactions: []Interaction{reply("primary", "from primary"), reply("backup", "from backup")},
+			want:         "from primary",
+		},
+		{
+			name:         "retries server error",
+			interactions: []Interaction{failure("primary", "status code: 503"), reply("primary", "from primary"), reply("backup", "from backup")},
+			want:         "from primary",
+		},
+		{
+			name:         "falls back after retries",
+			interactions: []Interaction{failure("primary", "status code: 503"), failure("primary", "status code: 502"), reply("backup", "from backup")},
+			want:         "from backup",
+		},
+		{
+			name:         "falls back on rate limit",
+			interactions: []Interaction{failure("primary", "429 Too Many Requests"), failure("primary", "429 Too Many Requests"), reply("backup", "from backup")},
+			want:         "from backup",
+		},
+		{
+			name:         "falls back on error without status",
+			interactions: []Interaction{failure("primary", "connection refused"), reply("backup", "from backup")},
+			want:         "from backup",
+		},
+		{
+			name:         "stops on bad request",
+			interactions: []Interaction{failure("primary", "status code: 400, context length exceeded"), reply("backup", "from backup")},
+			wantErr:      "400",
+		},
+		{
+			name:         "stops on auth failure",
+			interactions: []Interaction{failure("primary", "401 Unauthorized"), reply("backup", "from backup")},
+			wantErr:      "401",
+		},
+	}
+
+	calls := map[string]func(context.Context, model.ToolCallingChatModel, []*schema.Message) (*schema.Message, error){
+		"generate": func(ctx context.Context, m model.ToolCallingChatModel, input []*schema.Message) (*schema.Message, error) {
+			return m.Generate(ctx, input)
+		},
+		"stream": func(ctx context.Context, m model.ToolCallingChatModel, input []*schema.Message) (*schema.Message, error) {
+			stream, err := m.Stream(ctx, input)
+			if err != nil {
+				return nil, err
+			}
+			return schema.ConcatMessageStream(stream)
+		},
+	}
+
+	for _, tt := range tests {
+		for mode, call := range calls {
+			t.Run(tt.name+"/"+mode, func(t *testing.T) {
+				ctx := context.Background()
+				m, err := NewFactory(newChainConfig(t, tt.interactions)).CreateChatModel(ctx, "primary")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				msg, err := call(ctx, m, []*schema.Message{schema.UserMessage("hello")})
+				if tt.wantErr != "" {
+					if err == nil {
+						t.Fatalf("expected error containing %q, got reply %q", tt.wantErr, msg.Content)
+					}
+					if !strings.Contains(err.Error(), tt.wantErr) || strings.Contains(err.Error(), "backup") {
+						t.Fatalf("expected only the primary error containing %q, got %v", tt.wantErr, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if msg.Content != tt.want {
+					t.Fatalf("expected reply %q, got %q", tt.want, msg.Content)
+				}
+			})
+		}
+	}
+}
+
+func TestIsClientError(t *testing.T) {
+	tests := []struct {
+		code int
+		want bool
+	}{
+		{0, false},
+		{400, true},
+		{401, true},
+		{403, true},
+		{404, true},
+		{408, false},
+		{429, false},
+		{500, false},
+		{503, false},
+	}
+	for _, tt := range tests {
+		if got := isClientError(tt.code); got != tt.want {
+			t.Errorf("isClientError(%d) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestCreateProviderModel(t *testing.T) {
+	ctx := context.Background()
+	interactions := []Interaction{failure("primary", "status code: 503"), reply("primary", "from primary"), reply("backup", "from backup")}
+	m, err := NewFactory(newChainConfig(t, interactions)).CreateProviderModel(ctx, "primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The bare model neither retries nor falls back
+	if msg, err := m.Generate(ctx, []*schema.Message{schema.UserMessage("hello")}); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("expected the primary error, got %v, %v", msg, err)
+	}
+	msg, err := m.Generate(ctx, []*schema.Message{schema.UserMessage("hello")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Content != "from primary" {
+		t.Fatalf("expected reply %q, got %q", "from primary", msg.Content)
+	}
+}
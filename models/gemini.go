@@ -0,0 +1,54 @@
+package models
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino-ext/components/model/gemini"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/tk103331/eino-cli/config"
+	"google.golang.org/genai"
+)
+
+func init() {
+	Register("gemini", newGeminiModel, "api_key")
+	RegisterStatusCode(func(err error) int {
+		return errorStatus(err, func(e genai.APIError) int { return e.Code })
+	})
+}
+
+// newGeminiModel creates Gemini model
+func newGeminiModel(ctx context.Context, modelCfg *config.Model, providerCfg *config.Provider) (model.ToolCallingChatModel, error) {
+	httpClient, err := newHTTPClient(providerCfg)
+	if err != nil {
+		return nil, err
+	}
+	client, err := genai.NewClient(ctx, &genai.ClientConfig{
+		APIKey:      providerCfg.APIKey,
+		Backend:     genai.BackendGeminiAPI,
+		HTTPClient:  httpClient,
+		HTTPOptions: genai.HTTPOptions{BaseURL: providerCfg.BaseURL},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+
+	cfg := &gemini.Config{
+		Client:      client,
+		Model:       modelCfg.Model,
+		MaxTokens:   modelCfg.MaxTokens,
+		Temperature: float32Ptr(modelCfg.Temperature),
+		TopP:        float32Ptr(modelCfg.TopP),
+		TopK:        int32Ptr(modelCfg.TopK),
+	}
+	if budget, ok := thinkingBudgets[modelCfg.ReasoningEffort]; ok {
+		thinkingBudget := int32(budget)
+		cfg.ThinkingConfig = &genai.ThinkingConfig{ThinkingBudget: &thinkingBudget}
+	}
+	warnIgnored(modelCfg, providerCfg.Type, "stop", "seed", "presence_penalty", "frequency_penalty", "response_format")
+	if err := applyExtra(modelCfg, cfg); err != nil {
+		return nil, err
+	}
+
+	return gemini.NewChatModel(ctx, cfg)
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/tk103331/eino-cli/config"
+)
+
+// newHTTPClient creates the HTTP client of a provider with its timeout, proxy and header settings
+func newHTTPClient(providerCfg *config.Provider) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if providerCfg.Proxy != "" {
+		proxyURL, err := parseProxy(providerCfg.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	client := &http.Client{Transport: transport}
+	if len(providerCfg.Headers) > 0 {
+		client.Transport = &headerTransport{base: transport, headers: providerCfg.Headers}
+	}
+	if providerCfg.Timeout != "" {
+		timeout, err := parseTimeout(providerCfg.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		client.Timeout = timeout
+	}
+	return client, nil
+}
+
+// headerTransport adds the configured headers to every request
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// parseProxy parses a proxy URL such as http://127.0.0.1:7890 or socks5://127.0.0.1:1080
+func parseProxy(proxy string) (*url.URL, error) {
+	proxyURL, err := url.Parse(proxy)
+	if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy %q, must be a URL such as http://127.0.0.1:7890", proxy)
+	}
+	return proxyURL, nil
+}
+
+// parseTimeout parses a provider timeout such as 60s
+func parseTimeout(timeout string) (time.Duration, error) {
+	d, err := time.ParseDuration(timeout)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q, must be a positive duration such as 60s", timeout)
+	}
+	return d, nil
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tk103331/eino-cli/config"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	client, err := newHTTPClient(&config.Provider{
+		Headers: map[string]string{"X-Test": "stub", "Authorization": "Bearer configured"},
+		Timeout: "50ms",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer sdk")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	// Configured headers win over the ones set by the SDK
+	if received.Get("X-Test") != "stub" || received.Get("Authorization") != "Bearer configured" {
+		t.Errorf("expected the configured headers, got %v", received)
+	}
+	if req.Header.Get("Authorization") != "Bearer sdk" || req.Header.Get("X-Test") != "" {
+		t.Errorf("expected the caller's request to be left untouched, got %v", req.Header)
+	}
+
+	if _, err := client.Get(server.URL + "/slow"); err == nil || !strings.Contains(err.Error(), "Timeout") {
+		t.Errorf("expected the request to time out, got %v", err)
+	}
+}
+
+func TestNewHTTPClientInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider config.Provider
+		wantErr  string
+	}{
+		{"proxy without scheme", config.Provider{Proxy: "127.0.0.1:7890"}, "invalid proxy"},
+		{"timeout without unit", config.Provider{Timeout: "60"}, "invalid timeout"},
+		{"negative timeout", config.Provider{Timeout: "-1s"}, "invalid timeout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newHTTPClient(&tt.provider); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
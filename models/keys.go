@@ -0,0 +1,478 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/tk103331/eino-cli/config"
+	"github.com/tk103331/eino-cli/logger"
+)
+
+const (
+	keySelectionRoundRobin = "round_robin"
+	keySelectionRandom     = "random"
+	keySelectionWeighted   = "weighted"
+
+	defaultKeyCooldown = time.Minute
+
+	keyUsageFlushInterval = 5 * time.Second  // How long usage is accumulated in memory before it is saved
+	keyUsageLockTimeout   = 5 * time.Second  // How long a save waits for other processes
+	keyUsageLockStale     = 30 * time.Second // Age of a lock file left over by a crashed process
+)
+
+// KeyStats represents the usage of one provider API key
+type KeyStats struct {
+	Provider         string    `json:"provider"`
+	Key              string    `json:"key"` // Key label, never the key itself
+	ID               string    `json:"id"`  // Hash of the key, identifies keys that share a label
+	Calls            int       `json:"calls"`
+	Errors           int       `json:"errors"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	LastUsed         time.Time `json:"last_used"`
+	CooldownUntil    time.Time `json:"cooldown_until,omitempty"`
+}
+
+// add accumulates a usage delta
+func (s *KeyStats) add(delta KeyStats) {
+	s.Calls += delta.Calls
+	s.Errors += delta.Errors
+	s.PromptTokens += delta.PromptTokens
+	s.CompletionTokens += delta.CompletionTokens
+	if delta.LastUsed.After(s.LastUsed) {
+		s.LastUsed = delta.LastUsed
+	}
+	if delta.CooldownUntil.After(s.CooldownUntil) {
+		s.CooldownUntil = delta.CooldownUntil
+	}
+}
+
+// keyRegistry holds the state of every API key, shared by all models of a provider.
+// Usage is accumulated in memory and added to the key usage file periodically and on exit.
+type keyRegistry struct {
+	mu      sync.Mutex
+	stats   map[string]*KeyStats // provider/id -> stats of this process
+	pending map[string]*KeyStats // provider/id -> usage not yet saved
+	flush   *time.Timer          // Scheduled save of pending usage
+	next    map[string]int       // provider -> round robin position
+	saveMu  sync.Mutex           // Serializes saves of this process
+}
+
+var apiKeys = &keyRegistry{
+	stats:   make(map[string]*KeyStats),
+	pending: make(map[string]*KeyStats),
+	next:    make(map[string]int),
+}
+
+// KeyUsagePath returns the file where API key usage is accumulated, ~/.eino-cli/key_usage.json
+func KeyUsagePath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return filepath.Join(homeDir, ".eino-cli", "key_usage.json")
+}
+
+// LoadKeyUsage loads the accumulated API key usage, sorted by provider and key
+func LoadKeyUsage() ([]KeyStats, error) {
+	stats, err := readKeyUsage(KeyUsagePath())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Provider != stats[j].Provider {
+			return stats[i].Provider < stats[j].Provider
+		}
+		return stats[i].Key < stats[j].Key
+	})
+	return stats, nil
+}
+
+// FlushKeyUsage saves the API key usage accumulated in memory, called before the program exits
+func FlushKeyUsage() {
+	if err := apiKeys.save(); err != nil {
+		logger.Warn("MODEL", fmt.Sprintf("Failed to save API key usage: %v", err))
+	}
+}
+
+// readKeyUsage reads a key usage file, a missing file has no usage
+func readKeyUsage(path string) ([]KeyStats, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stats []KeyStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, fmt.Errorf("failed to parse key usage: %w", err)
+	}
+	return stats, nil
+}
+
+// get returns the state of a key, caller must hold the lock
+func (r *keyRegistry) get(provider string, key pooledKey) *KeyStats {
+	id := provider + "/" + key.id
+	s, ok := r.stats[id]
+	if !ok {
+		s = &KeyStats{Provider: provider, Key: key.label, ID: key.id}
+		r.stats[id] = s
+	}
+	return s
+}
+
+// record adds a usage delta to a key and schedules saving it
+func (r *keyRegistry) record(provider string, key pooledKey, delta KeyStats) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.get(provider, key)
+	delta.LastUsed = time.Now()
+	delta.CooldownUntil = s.CooldownUntil
+	s.add(delta)
+
+	id := provider + "/" + key.id
+	p, ok := r.pending[id]
+	if !ok {
+		p = &KeyStats{Provider: provider, Key: key.label, ID: key.id}
+		r.pending[id] = p
+	}
+	p.add(delta)
+	if r.flush == nil {
+		r.flush = time.AfterFunc(keyUsageFlushInterval, FlushKeyUsage)
+	}
+}
+
+// restoreCooldowns takes over cool-downs saved by earlier runs, so that rejected keys stay out of rotation
+func (r *keyRegistry) restoreCooldowns(provider string, keys []pooledKey) {
+	stats, err := LoadKeyUsage()
+	if err != nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, saved := range stats {
+		if saved.Provider != provider {
+			continue
+		}
+		for _, key := range keys {
+			if key.id != saved.ID {
+				continue
+			}
+			if s := r.get(provider, key); saved.CooldownUntil.After(s.CooldownUntil) {
+				s.CooldownUntil = saved.CooldownUntil
+			}
+		}
+	}
+}
+
+// cooldown takes a key out of rotation until the cool-down period has passed
+func (r *keyRegistry) cooldown(provider string, key pooledKey, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.get(provider, key).CooldownUntil = time.Now().Add(d)
+}
+
+// save adds the pending usage to the key usage file. The file is locked while it is updated,
+// so that concurrent processes do not lose each other's usage.
+func (r *keyRegistry) save() error {
+	r.saveMu.Lock()
+	defer r.saveMu.Unlock()
+
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = make(map[string]*KeyStats)
+	if r.flush != nil {
+		r.flush.Stop()
+		r.flush = nil
+	}
+	r.mu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	err := saveKeyUsage(KeyUsagePath(), pending)
+	if err != nil {
+		// Keep the usage for the next save
+		r.mu.Lock()
+		for id, delta := range pending {
+			if p, ok := r.pending[id]; ok {
+				delta.add(*p)
+			}
+			r.pending[id] = delta
+		}
+		r.mu.Unlock()
+	}
+	return err
+}
+
+// saveKeyUsage adds usage deltas to a key usage file while holding its lock
+func saveKeyUsage(path string, deltas map[string]*KeyStats) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	all, err := readKeyUsage(path)
+	if err != nil {
+		return err
+	}
+	for _, delta := range deltas {
+		found := false
+		for i := range all {
+			if all[i].Provider == delta.Provider && all[i].ID == delta.ID {
+				all[i].Key = delta.Key
+				all[i].add(*delta)
+				found = true
+				break
+			}
+		}
+		if !found {
+			all = append(all, *delta)
+		}
+	}
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// lockFile creates a lock file shared by all eino-cli processes and returns the function releasing it.
+// A lock older than keyUsageLockStale is left over by a crashed process and taken over.
+func lockFile(path string) (func(), error) {
+	deadline := time.Now().Add(keyUsageLockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > keyUsageLockStale {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock %s", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// pooledKey is a model bound to one API key
+type pooledKey struct {
+	id     string // Hash of the key
+	label  string
+	weight int
+	model  model.ToolCallingChatModel
+}
+
+// keyPoolModel spreads calls across models created with different API keys of one provider
+type keyPoolModel struct {
+	provider  string
+	selection string
+	cooldown  time.Duration
+	keys      []pooledKey
+}
+
+// newKeyPool creates a model for every API key of the provider and wraps them in a pool
+func newKeyPool(providerName string, providerCfg *config.Provider, create func(*config.Provider) (model.ToolCallingChatModel, error)) (model.ToolCallingChatModel, error) {
+	pool := &keyPoolModel{
+		provider:  providerName,
+		selection: providerCfg.KeySelection,
+		cooldown:  defaultKeyCooldown,
+	}
+	if pool.selection == "" {
+		pool.selection = keySelectionRoundRobin
+	}
+	switch pool.selection {
+	case keySelectionRoundRobin, keySelectionRandom, keySelectionWeighted:
+	default:
+		return nil, fmt.Errorf("unsupported key_selection: %s, must be round_robin, random or weighted", pool.selection)
+	}
+	if providerCfg.KeyCooldown != "" {
+		d, err := time.ParseDuration(providerCfg.KeyCooldown)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key_cooldown %q: %w", providerCfg.KeyCooldown, err)
+		}
+		pool.cooldown = d
+	}
+
+	for i, key := range providerCfg.APIKeys {
+		if key.Key == "" {
+			return nil, fmt.Errorf("api_keys[%d] of provider %s is empty", i, providerName)
+		}
+		keyCfg := *providerCfg
+		keyCfg.APIKey = key.Key
+		keyCfg.APIKeys = nil
+		m, err := create(&keyCfg)
+		if err != nil {
+			return nil, err
+		}
+		weight := key.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		pool.keys = append(pool.keys, pooledKey{id: key.ID(), label: key.Label(), weight: weight, model: m})
+	}
+	apiKeys.restoreCooldowns(providerName, pool.keys)
+	return pool, nil
+}
+
+// Generate implements model.BaseChatModel, moving on to the next key when a key is rejected
+func (p *keyPoolModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	var lastErr error
+	for _, key := range p.order() {
+		msg, err := key.model.Generate(ctx, input, opts...)
+		if err == nil {
+			delta := KeyStats{Calls: 1}
+			if msg.ResponseMeta != nil && msg.ResponseMeta.Usage != nil {
+				delta.PromptTokens = msg.ResponseMeta.Usage.PromptTokens
+				delta.CompletionTokens = msg.ResponseMeta.Usage.CompletionTokens
+			}
+			apiKeys.record(p.provider, key, delta)
+			return msg, nil
+		}
+		lastErr = err
+		if !p.reject(key, err) {
+			return nil, err
+		}
+	}
+	return nil, p.exhausted(lastErr)
+}
+
+// Stream implements model.BaseChatModel, moving on to the next key when a key is rejected
+func (p *keyPoolModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	var lastErr error
+	for _, key := range p.order() {
+		stream, err := key.model.Stream(ctx, input, opts...)
+		if err == nil {
+			apiKeys.record(p.provider, key, KeyStats{Calls: 1})
+			// Count token usage reported by the stream
+			return schema.StreamReaderWithConvert(stream, func(msg *schema.Message) (*schema.Message, error) {
+				if msg != nil && msg.ResponseMeta != nil && msg.ResponseMeta.Usage != nil {
+					apiKeys.record(p.provider, key, KeyStats{
+						PromptTokens:     msg.ResponseMeta.Usage.PromptTokens,
+						CompletionTokens: msg.ResponseMeta.Usage.CompletionTokens,
+					})
+				}
+				return msg, nil
+			}), nil
+		}
+		lastErr = err
+		if !p.reject(key, err) {
+			return nil, err
+		}
+	}
+	return nil, p.exhausted(lastErr)
+}
+
+// WithTools implements model.ToolCallingChatModel, binding the tools to the model of every key
+func (p *keyPoolModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	pool := *p
+	pool.keys = make([]pooledKey, len(p.keys))
+	for i, key := range p.keys {
+		m, err := key.model.WithTools(tools)
+		if err != nil {
+			return nil, err
+		}
+		pool.keys[i] = pooledKey{id: key.id, label: key.label, weight: key.weight, model: m}
+	}
+	return &pool, nil
+}
+
+// IsCallbacksEnabled reports that callbacks are handled by the wrapped models
+func (p *keyPoolModel) IsCallbacksEnabled() bool {
+	return true
+}
+
+// reject records a failed call, taking the key out of rotation on 401/429; it reports whether another key should be tried
+func (p *keyPoolModel) reject(key pooledKey, err error) bool {
+	code := statusCode(err)
+	rejected := code == http.StatusUnauthorized || code == http.StatusTooManyRequests
+	if rejected {
+		logger.Warn("MODEL", fmt.Sprintf("API key %s of provider %s rejected with %d, cooling down for %v", key.label, p.provider, code, p.cooldown))
+		apiKeys.cooldown(p.provider, key, p.cooldown)
+	}
+	apiKeys.record(p.provider, key, KeyStats{Calls: 1, Errors: 1})
+	return rejected
+}
+
+// exhausted builds the error returned when no key is left
+func (p *keyPoolModel) exhausted(lastErr error) error {
+	msg := fmt.Sprintf("all API keys of provider %s are cooling down", p.provider)
+	if lastErr == nil {
+		return errors.New(msg)
+	}
+	return fmt.Errorf("%s: %w", msg, lastErr)
+}
+
+// order returns the available keys in the order they should be tried
+func (p *keyPoolModel) order() []pooledKey {
+	apiKeys.mu.Lock()
+	defer apiKeys.mu.Unlock()
+
+	now := time.Now()
+	var available []pooledKey
+	for _, key := range p.keys {
+		if apiKeys.get(p.provider, key).CooldownUntil.Before(now) {
+			available = append(available, key)
+		}
+	}
+	if len(available) <= 1 {
+		return available
+	}
+
+	switch p.selection {
+	case keySelectionRandom:
+		rand.Shuffle(len(available), func(i, j int) { available[i], available[j] = available[j], available[i] })
+	case keySelectionWeighted:
+		// Weighted random order without replacement
+		ordered := make([]pooledKey, 0, len(available))
+		for len(available) > 0 {
+			total := 0
+			for _, key := range available {
+				total += key.weight
+			}
+			n := rand.Intn(total)
+			for i, key := range available {
+				if n < key.weight {
+					ordered = append(ordered, key)
+					available = append(available[:i], available[i+1:]...)
+					break
+				}
+				n -= key.weight
+			}
+		}
+		available = ordered
+	default:
+		start := apiKeys.next[p.provider] % len(available)
+		apiKeys.next[p.provider]++
+		available = append(available[start:], available[:start]...)
+	}
+	return available
+}
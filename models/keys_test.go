@@ -0,0 +1,58 @@
+package models
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/tk103331/eino-cli/config"
+)
+
+func TestAPIKeyID(t *testing.T) {
+	short1, short2 := config.APIKey{Key: "abc"}, config.APIKey{Key: "xyz"}
+	long1, long2 := config.APIKey{Key: "sk-first-1234"}, config.APIKey{Key: "sk-second-1234"}
+	if short1.Label() != short2.Label() || long1.Label() != long2.Label() {
+		t.Fatal("expected the masked labels to collide")
+	}
+	if short1.ID() == short2.ID() || long1.ID() == long2.ID() {
+		t.Fatal("expected different keys to have different IDs")
+	}
+	if long1.ID() != (config.APIKey{Name: "named", Key: "sk-first-1234"}).ID() {
+		t.Fatal("expected the ID to depend on the key only")
+	}
+}
+
+func TestSaveKeyUsageConcurrently(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key_usage.json")
+	keys := []config.APIKey{{Key: "sk-first-1234"}, {Key: "sk-second-1234"}}
+
+	const saves = 20
+	var wg sync.WaitGroup
+	for i := 0; i < saves; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			deltas := make(map[string]*KeyStats)
+			for _, key := range keys {
+				deltas[key.ID()] = &KeyStats{Provider: "openai", Key: key.Label(), ID: key.ID(), Calls: 1, PromptTokens: 10}
+			}
+			if err := saveKeyUsage(path, deltas); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats, err := readKeyUsage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != len(keys) {
+		t.Fatalf("expected %d keys, got %d", len(keys), len(stats))
+	}
+	for _, s := range stats {
+		if s.Calls != saves || s.PromptTokens != saves*10 {
+			t.Errorf("key %s: expected %d calls and %d prompt tokens, got %d and %d", s.ID, saves, saves*10, s.Calls, s.PromptTokens)
+		}
+	}
+}
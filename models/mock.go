@@ -0,0 +1,162 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/tk103331/eino-cli/config"
+)
+
+func init() {
+	// Mock responses are scripted per call, caching them would break the script
+	RegisterUncached("mock", newMockModel, "cassette")
+}
+
+// mockModel replays the interactions of a cassette in order, without network access
+type mockModel struct {
+	model string
+	tools []*schema.ToolInfo // Only reported to callbacks
+	state *mockState
+}
+
+// mockState is shared by the copies returned by WithTools, so that a conversation consumes the cassette once
+type mockState struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	patterns     []*regexp.Regexp
+	used         []bool
+}
+
+// newMockModel creates a mock model replaying the cassette of the provider
+func newMockModel(ctx context.Context, modelCfg *config.Model, providerCfg *config.Provider) (model.ToolCallingChatModel, error) {
+	if providerCfg.Cassette == "" {
+		return nil, errors.New("mock provider requires cassette")
+	}
+	cassette, err := LoadCassette(providerCfg.Cassette)
+	if err != nil {
+		return nil, err
+	}
+	state := &mockState{
+		interactions: cassette.Interactions,
+		patterns:     make([]*regexp.Regexp, len(cassette.Interactions)),
+		used:         make([]bool, len(cassette.Interactions)),
+	}
+	for i, interaction := range cassette.Interactions {
+		if interaction.Match == "" {
+			continue
+		}
+		if state.patterns[i], err = regexp.Compile(interaction.Match); err != nil {
+			return nil, fmt.Errorf("invalid match of interaction %d in %s: %w", i+1, providerCfg.Cassette, err)
+		}
+	}
+	return &mockModel{model: modelCfg.Model, state: state}, nil
+}
+
+// next takes the first unused interaction for the model whose match accepts the input
+func (m *mockModel) next(input []*schema.Message) (*Interaction, error) {
+	m.state.mu.Lock()
+	defer m.state.mu.Unlock()
+
+	last := lastContent(input)
+	for i := range m.state.interactions {
+		interaction := &m.state.interactions[i]
+		if m.state.used[i] || (interaction.Model != "" && interaction.Model != m.model) {
+			continue
+		}
+		if pattern := m.state.patterns[i]; pattern != nil && !pattern.MatchString(last) {
+			continue
+		}
+		m.state.used[i] = true
+		if interaction.Error != "" {
+			return nil, errors.New(interaction.Error)
+		}
+		if interaction.Response == nil {
+			return nil, fmt.Errorf("interaction %d of the cassette has no response", i+1)
+		}
+		return interaction, nil
+	}
+	return nil, fmt.Errorf("no cassette interaction left for model %s and input %q", m.model, last)
+}
+
+// Generate implements model.BaseChatModel
+func (m *mockModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	ctx = callbacks.OnStart(ctx, m.callbackInput(input))
+	interaction, err := m.next(input)
+	if err != nil {
+		callbacks.OnError(ctx, err)
+		return nil, err
+	}
+	msg := interaction.Response.toMessage(interaction.Response.Content)
+	callbacks.OnEnd(ctx, m.callbackOutput(msg))
+	return msg, nil
+}
+
+// Stream implements model.BaseChatModel, sending the content in the scripted chunks and tool calls with the last chunk
+func (m *mockModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	ctx = callbacks.OnStart(ctx, m.callbackInput(input))
+	interaction, err := m.next(input)
+	if err != nil {
+		callbacks.OnError(ctx, err)
+		return nil, err
+	}
+	response := interaction.Response
+	chunks := response.Chunks
+	if len(chunks) == 0 {
+		chunks = []string{response.Content}
+	}
+	messages := make([]*schema.Message, len(chunks))
+	for i, chunk := range chunks {
+		messages[i] = &schema.Message{Role: schema.Assistant, Content: chunk}
+	}
+	last := response.toMessage(chunks[len(chunks)-1])
+	messages[len(messages)-1] = last
+
+	outputs := make([]*model.CallbackOutput, len(messages))
+	for i, msg := range messages {
+		outputs[i] = m.callbackOutput(msg)
+	}
+	_, stream := callbacks.OnEndWithStreamOutput(ctx, schema.StreamReaderFromArray(outputs))
+	return schema.StreamReaderWithConvert(stream, func(output *model.CallbackOutput) (*schema.Message, error) {
+		return output.Message, nil
+	}), nil
+}
+
+// callbackInput describes a call to callback handlers
+func (m *mockModel) callbackInput(input []*schema.Message) *model.CallbackInput {
+	return &model.CallbackInput{Messages: input, Tools: m.tools, Config: &model.Config{Model: m.model}}
+}
+
+// callbackOutput describes a response message to callback handlers, with the scripted token usage
+func (m *mockModel) callbackOutput(msg *schema.Message) *model.CallbackOutput {
+	output := &model.CallbackOutput{Message: msg, Config: &model.Config{Model: m.model}}
+	if msg.ResponseMeta != nil && msg.ResponseMeta.Usage != nil {
+		usage := msg.ResponseMeta.Usage
+		output.TokenUsage = &model.TokenUsage{
+			PromptTokens:     usage.PromptTokens,
+			CompletionTokens: usage.CompletionTokens,
+			TotalTokens:      usage.TotalTokens,
+		}
+	}
+	return output
+}
+
+// WithTools implements model.ToolCallingChatModel, responses are scripted so the tools are only reported to callbacks
+func (m *mockModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return &mockModel{model: m.model, tools: tools, state: m.state}, nil
+}
+
+// GetType implements components.Typer
+func (m *mockModel) GetType() string {
+	return "Mock"
+}
+
+// IsCallbacksEnabled reports that the mock model calls the callback handlers itself, like the provider models
+func (m *mockModel) IsCallbacksEnabled() bool {
+	return true
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestMockModelCallbacks(t *testing.T) {
+	usage := Interaction{Model: "primary", Response: &MockMessage{Content: "hi", PromptTokens: 3, CompletionTokens: 2}}
+	for _, stream := range []bool{false, true} {
+		name := "generate"
+		if stream {
+			name = "stream"
+		}
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			// The chain reports that its models handle callbacks, so the mock model must call them itself
+			m, err := NewFactory(newChainConfig(t, []Interaction{usage})).CreateChatModel(ctx, "primary")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !components.IsCallbacksEnabled(m) {
+				t.Fatal("expected the chain to handle callbacks")
+			}
+
+			var (
+				mu     sync.Mutex
+				tokens int
+				starts int
+			)
+			handler := callbacks.NewHandlerBuilder().
+				OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+					mu.Lock()
+					starts++
+					mu.Unlock()
+					return ctx
+				}).
+				OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+					if out := model.ConvCallbackOutput(output); out.TokenUsage != nil {
+						mu.Lock()
+						tokens += out.TokenUsage.TotalTokens
+						mu.Unlock()
+					}
+					return ctx
+				}).
+				OnEndWithStreamOutputFn(func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+					defer output.Close()
+					for {
+						chunk, err := output.Recv()
+						if errors.Is(err, io.EOF) {
+							return ctx
+						}
+						if err != nil {
+							t.Error(err)
+							return ctx
+						}
+						if out := model.ConvCallbackOutput(chunk); out.TokenUsage != nil {
+							mu.Lock()
+							tokens += out.TokenUsage.TotalTokens
+							mu.Unlock()
+						}
+					}
+				}).
+				Build()
+			ctx = callbacks.InitCallbacks(ctx, &callbacks.RunInfo{Name: "test"}, handler)
+
+			input := []*schema.Message{schema.UserMessage("hello")}
+			if stream {
+				sr, err := m.Stream(ctx, input)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := schema.ConcatMessageStream(sr); err != nil {
+					t.Fatal(err)
+				}
+			} else if _, err := m.Generate(ctx, input); err != nil {
+				t.Fatal(err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if starts != 1 || tokens != 5 {
+				t.Fatalf("expected one call reporting 5 tokens, got %d calls and %d tokens", starts, tokens)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/cloudwego/eino-ext/components/model/ollama"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/ollama/ollama/api"
+	"github.com/tk103331/eino-cli/config"
+)
+
+func init() {
+	Register("ollama", newOllamaModel)
+	RegisterStatusCode(func(err error) int {
+		return errorStatus(err, func(e api.StatusError) int { return e.StatusCode })
+	})
+}
+
+// newOllamaModel creates Ollama model
+func newOllamaModel(ctx context.Context, modelCfg *config.Model, providerCfg *config.Provider) (model.ToolCallingChatModel, error) {
+	httpClient, err := newHTTPClient(providerCfg)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &ollama.ChatModelConfig{
+		Model:      modelCfg.Model,
+		BaseURL:    providerCfg.BaseURL,
+		HTTPClient: httpClient,
+	}
+
+	// Ollama sampling options are set through the Options field, whose zero values are omitted from requests.
+	// Explicit settings are therefore also added to the request body, so that e.g. temperature: 0 is sent.
+	if options := ollamaOptions(modelCfg); len(options) > 0 {
+		cfg.Options = &api.Options{
+			NumPredict:       deref(modelCfg.MaxTokens),
+			Temperature:      deref(float32Ptr(modelCfg.Temperature)),
+			TopP:             deref(float32Ptr(modelCfg.TopP)),
+			TopK:             deref(modelCfg.TopK),
+			Seed:             deref(modelCfg.Seed),
+			PresencePenalty:  deref(float32Ptr(modelCfg.PresencePenalty)),
+			FrequencyPenalty: deref(float32Ptr(modelCfg.FrequencyPenalty)),
+			Stop:             modelCfg.Stop,
+		}
+		httpClient.Transport = &ollamaOptionsTransport{base: httpClient.Transport, options: options}
+	}
+	if modelCfg.ResponseFormat == responseFormatJSONObject {
+		cfg.Format = json.RawMessage(`"json"`)
+	}
+	if modelCfg.ReasoningEffort != "" {
+		cfg.Thinking = &api.ThinkValue{Value: modelCfg.ReasoningEffort}
+	}
+	if err := applyExtra(modelCfg, cfg); err != nil {
+		return nil, err
+	}
+
+	return ollama.NewChatModel(ctx, cfg)
+}
+
+// ollamaOptions returns the sampling options set in the model configuration by their Ollama names
+func ollamaOptions(modelCfg *config.Model) map[string]any {
+	options := make(map[string]any)
+	set := func(name string, value any, ok bool) {
+		if ok {
+			options[name] = value
+		}
+	}
+	set("num_predict", deref(modelCfg.MaxTokens), modelCfg.MaxTokens != nil)
+	set("temperature", deref(modelCfg.Temperature), modelCfg.Temperature != nil)
+	set("top_p", deref(modelCfg.TopP), modelCfg.TopP != nil)
+	set("top_k", deref(modelCfg.TopK), modelCfg.TopK != nil)
+	set("seed", deref(modelCfg.Seed), modelCfg.Seed != nil)
+	set("presence_penalty", deref(modelCfg.PresencePenalty), modelCfg.PresencePenalty != nil)
+	set("frequency_penalty", deref(modelCfg.FrequencyPenalty), modelCfg.FrequencyPenalty != nil)
+	set("stop", modelCfg.Stop, len(modelCfg.Stop) > 0)
+	return options
+}
+
+// ollamaOptionsTransport adds the configured options missing from the options of chat requests,
+// which are the explicit zero values dropped by api.Options
+type ollamaOptionsTransport struct {
+	base    http.RoundTripper
+	options map[string]any
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *ollamaOptionsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPost || !strings.HasSuffix(req.URL.Path, "/api/chat") || req.Body == nil {
+		return t.base.RoundTrip(req)
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if patched, err := t.patch(data); err == nil {
+		data = patched
+	}
+
+	// A RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.ContentLength = int64(len(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	return t.base.RoundTrip(req)
+}
+
+// patch adds the missing options to a chat request body
+func (t *ollamaOptionsTransport) patch(data []byte) ([]byte, error) {
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, err
+	}
+	options := make(map[string]any)
+	if raw, ok := body["options"]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &options); err != nil {
+			return nil, err
+		}
+	}
+	for name, value := range t.options {
+		if _, ok := options[name]; !ok {
+			options[name] = value
+		}
+	}
+	raw, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+	body["options"] = raw
+	return json.Marshal(body)
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/tk103331/eino-cli/config"
+)
+
+func TestOllamaExplicitZeroOptions(t *testing.T) {
+	var options map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Options map[string]any `json:"options"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		options = body.Options
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Write([]byte(`{"model":"llama3","message":{"role":"assistant","content":"ok"},"done":true}` + "\n"))
+	}))
+	defer server.Close()
+
+	zero, half, seed := 0.0, 0.5, 0
+	modelCfg := &config.Model{Model: "llama3", Temperature: &zero, TopP: &half, Seed: &seed}
+	m, err := newOllamaModel(context.Background(), modelCfg, &config.Provider{Type: "ollama", BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Generate(context.Background(), []*schema.Message{schema.UserMessage("hello")}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]float64{"temperature": 0, "top_p": 0.5, "seed": 0}
+	for name, value := range want {
+		got, ok := options[name]
+		if !ok {
+			t.Errorf("option %s was not sent, got options %v", name, options)
+			continue
+		}
+		if got != value {
+			t.Errorf("option %s: expected %v, got %v", name, value, got)
+		}
+	}
+	if _, ok := options["top_k"]; ok {
+		t.Errorf("unset option top_k was sent: %v", options)
+	}
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino-ext/components/model/openai"
+	"github.com/cloudwego/eino/components/model"
+	goopenai "github.com/meguminnnnnnnnn/go-openai"
+	"github.com/tk103331/eino-cli/config"
+)
+
+// defaultAzureAPIVersion is used when an azure provider does not set api_version
+const defaultAzureAPIVersion = "2024-10-21"
+
+func init() {
+	Register("openai", newOpenAIModel)
+	Register("azure", newOpenAIModel, "base_url")
+	// OpenAI compatible servers, base_url defaults to their usual endpoint
+	Register("openrouter", newOpenAICompatibleModel("https://openrouter.ai/api/v1"))
+	Register("vllm", newOpenAICompatibleModel("http://localhost:8000/v1"))
+	Register("lmstudio", newOpenAICompatibleModel("http://localhost:1234/v1"))
+	RegisterStatusCode(openAIStatusCode)
+}
+
+// openAIStatusCode returns the status code of an OpenAI client error, also used by qwen
+func openAIStatusCode(err error) int {
+	if code := errorStatus(err, func(e *goopenai.APIError) int { return e.HTTPStatusCode }); code != 0 {
+		return code
+	}
+	return errorStatus(err, func(e *goopenai.RequestError) int { return e.HTTPStatusCode })
+}
+
+// newOpenAICompatibleModel returns a constructor for an OpenAI compatible server with a default base URL
+func newOpenAICompatibleModel(defaultBaseURL string) Constructor {
+	return func(ctx context.Context, modelCfg *config.Model, providerCfg *config.Provider) (model.ToolCallingChatModel, error) {
+		compatibleCfg := *providerCfg
+		if compatibleCfg.BaseURL == "" {
+			compatibleCfg.BaseURL = defaultBaseURL
+		}
+		return newOpenAIModel(ctx, modelCfg, &compatibleCfg)
+	}
+}
+
+// newOpenAIModel creates OpenAI model, or Azure OpenAI model for the azure provider type
+func newOpenAIModel(ctx context.Context, modelCfg *config.Model, providerCfg *config.Provider) (model.ToolCallingChatModel, error) {
+	if providerCfg.Organization != "" {
+		// Sent as a header, the eino-ext config has no organization field
+		orgCfg := *providerCfg
+		orgCfg.Headers = map[string]string{"OpenAI-Organization": providerCfg.Organization}
+		for key, value := range providerCfg.Headers {
+			orgCfg.Headers[key] = value
+		}
+		providerCfg = &orgCfg
+	}
+	httpClient, err := newHTTPClient(providerCfg)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &openai.ChatModelConfig{
+		Model:            modelCfg.Model,
+		BaseURL:          providerCfg.BaseURL,
+		APIKey:           providerCfg.APIKey,
+		HTTPClient:       httpClient,
+		MaxTokens:        modelCfg.MaxTokens,
+		Temperature:      float32Ptr(modelCfg.Temperature),
+		TopP:             float32Ptr(modelCfg.TopP),
+		Stop:             modelCfg.Stop,
+		Seed:             modelCfg.Seed,
+		PresencePenalty:  float32Ptr(modelCfg.PresencePenalty),
+		FrequencyPenalty: float32Ptr(modelCfg.FrequencyPenalty),
+		ReasoningEffort:  openai.ReasoningEffortLevel(modelCfg.ReasoningEffort),
+	}
+	if modelCfg.ResponseFormat == responseFormatJSONObject {
+		cfg.ResponseFormat = &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject}
+	}
+	if providerCfg.Type == "azure" {
+		cfg.ByAzure = true
+		cfg.APIVersion = providerCfg.APIVersion
+		if cfg.APIVersion == "" {
+			cfg.APIVersion = defaultAzureAPIVersion
+		}
+		if deployments := providerCfg.Deployments; len(deployments) > 0 {
+			cfg.AzureModelMapperFunc = func(name string) string {
+				if deployment, ok := deployments[name]; ok {
+					return deployment
+				}
+				return name
+			}
+		}
+	}
+	warnIgnored(modelCfg, providerCfg.Type, "top_k")
+	if err := applyExtra(modelCfg, cfg); err != nil {
+		return nil, err
+	}
+
+	return openai.NewChatModel(ctx, cfg)
+}
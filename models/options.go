@@ -0,0 +1,104 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tk103331/eino-cli/config"
+	"github.com/tk103331/eino-cli/logger"
+)
+
+const (
+	responseFormatText       = "text"
+	responseFormatJSONObject = "json_object"
+)
+
+// thinkingBudgets maps reasoning_effort to a token budget for providers that configure thinking by budget
+var thinkingBudgets = map[string]int{
+	"low":    1024,
+	"medium": 4096,
+	"high":   16384,
+}
+
+// float32Ptr converts an optional float64 setting to the float32 used by most providers
+func float32Ptr(v *float64) *float32 {
+	if v == nil {
+		return nil
+	}
+	f := float32(*v)
+	return &f
+}
+
+// int32Ptr converts an optional int setting to int32
+func int32Ptr(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	i := int32(*v)
+	return &i
+}
+
+// deref returns the value of an optional setting, or the zero value
+func deref[T any](v *T) T {
+	var zero T
+	if v == nil {
+		return zero
+	}
+	return *v
+}
+
+// warnIgnored logs the options that are set but not supported by the provider, instead of dropping them silently
+func warnIgnored(modelCfg *config.Model, providerType string, options ...string) {
+	var ignored []string
+	for _, option := range options {
+		if optionSet(modelCfg, option) {
+			ignored = append(ignored, option)
+		}
+	}
+	if len(ignored) > 0 {
+		logger.Warn("MODEL", fmt.Sprintf("Model %s: %s not supported by provider type %s, ignored", modelCfg.Model, strings.Join(ignored, ", "), providerType))
+	}
+}
+
+// optionSet reports whether a sampling option is set in the model configuration
+func optionSet(modelCfg *config.Model, option string) bool {
+	switch option {
+	case "max_tokens":
+		return modelCfg.MaxTokens != nil
+	case "temperature":
+		return modelCfg.Temperature != nil
+	case "top_p":
+		return modelCfg.TopP != nil
+	case "top_k":
+		return modelCfg.TopK != nil
+	case "stop":
+		return len(modelCfg.Stop) > 0
+	case "seed":
+		return modelCfg.Seed != nil
+	case "presence_penalty":
+		return modelCfg.PresencePenalty != nil
+	case "frequency_penalty":
+		return modelCfg.FrequencyPenalty != nil
+	case "response_format":
+		return modelCfg.ResponseFormat != "" && modelCfg.ResponseFormat != responseFormatText
+	case "reasoning_effort":
+		return modelCfg.ReasoningEffort != ""
+	}
+	return false
+}
+
+// applyExtra sets the extra settings on a provider model config, matching keys to its JSON field names
+func applyExtra(modelCfg *config.Model, providerCfg any) error {
+	if len(modelCfg.Extra) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(modelCfg.Extra)
+	if err != nil {
+		return fmt.Errorf("invalid extra settings of model %s: %w", modelCfg.Model, err)
+	}
+	if err := json.Unmarshal(data, providerCfg); err != nil {
+		return fmt.Errorf("invalid extra settings of model %s: %w", modelCfg.Model, err)
+	}
+	return nil
+}
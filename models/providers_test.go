@@ -0,0 +1,269 @@
+package models
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/tk103331/eino-cli/config"
+)
+
+// stubRequest is a request received by the provider stub
+type stubRequest struct {
+	host   string
+	path   string
+	query  url.Values
+	header http.Header
+}
+
+// newProviderStub starts a server answering every request with a client error, so that SDKs do not retry
+func newProviderStub(t *testing.T) (*httptest.Server, func() *stubRequest) {
+	t.Helper()
+	var (
+		mu   sync.Mutex
+		last *stubRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		last = &stubRequest{host: r.Host, path: r.URL.Path, query: r.URL.Query(), header: r.Header.Clone()}
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		if r.URL.Path == "/api/chat" {
+			// Ollama reports errors as a plain message
+			w.Write([]byte(`{"error":"stub"}`))
+			return
+		}
+		w.Write([]byte(`{"error":{"message":"stub","type":"invalid_request_error","code":"stub"}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() *stubRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return last
+	}
+}
+
+func TestProviderConnection(t *testing.T) {
+	tests := []struct {
+		name       string
+		provider   config.Provider
+		basePath   string // Appended to the stub URL as base_url
+		wantPath   string // Expected path prefix of the chat request
+		wantHost   string // Expected host when base_url is left to the provider default, only checked through the proxy
+		wantQuery  url.Values
+		authHeader string // Header carrying the API key
+		authValue  string
+		wantHeader map[string]string // Further headers the provider must send
+	}{
+		{
+			name:       "openai",
+			provider:   config.Provider{Type: "openai", APIKey: "sk-test", Organization: "org-test"},
+			basePath:   "/v1",
+			wantPath:   "/v1/chat/completions",
+			authHeader: "Authorization",
+			authValue:  "Bearer sk-test",
+			wantHeader: map[string]string{"OpenAI-Organization": "org-test"},
+		},
+		{
+			name:       "azure",
+			provider:   config.Provider{Type: "azure", APIKey: "sk-test", Deployments: map[string]string{"test-model": "test-deployment"}},
+			wantPath:   "/openai/deployments/test-deployment/chat/completions",
+			authHeader: "api-key",
+			authValue:  "sk-test",
+			wantQuery:  url.Values{"api-version": {defaultAzureAPIVersion}},
+		},
+		{
+			name:       "azure api_version",
+			provider:   config.Provider{Type: "azure", APIKey: "sk-test", APIVersion: "2025-01-01-preview"},
+			wantPath:   "/openai/deployments/test-model/chat/completions",
+			authHeader: "api-key",
+			authValue:  "sk-test",
+			wantQuery:  url.Values{"api-version": {"2025-01-01-preview"}},
+		},
+		{
+			name:       "organization with headers",
+			provider:   config.Provider{Type: "openai", APIKey: "sk-test", Organization: "org-test", Headers: map[string]string{"OpenAI-Organization": "org-header"}},
+			basePath:   "/v1",
+			wantPath:   "/v1/chat/completions",
+			authHeader: "Authorization",
+			authValue:  "Bearer sk-test",
+			wantHeader: map[string]string{"OpenAI-Organization": "org-header"},
+		},
+		{
+			name:       "openrouter",
+			provider:   config.Provider{Type: "openrouter", APIKey: "sk-test"},
+			basePath:   "/api/v1",
+			wantPath:   "/api/v1/chat/completions",
+			authHeader: "Authorization",
+			authValue:  "Bearer sk-test",
+		},
+		{
+			name:     "vllm",
+			provider: config.Provider{Type: "vllm"},
+			wantPath: "/v1/chat/completions",
+			wantHost: "localhost:8000",
+		},
+		{
+			name:     "lmstudio",
+			provider: config.Provider{Type: "lmstudio"},
+			wantPath: "/v1/chat/completions",
+			wantHost: "localhost:1234",
+		},
+		{
+			name:       "claude",
+			provider:   config.Provider{Type: "claude", APIKey: "sk-test"},
+			wantPath:   "/v1/messages",
+			authHeader: "X-Api-Key",
+			authValue:  "sk-test",
+		},
+		{
+			name:       "deepseek",
+			provider:   config.Provider{Type: "deepseek", APIKey: "sk-test"},
+			wantPath:   "/chat/completions",
+			authHeader: "Authorization",
+			authValue:  "Bearer sk-test",
+		},
+		{
+			name:       "qwen",
+			provider:   config.Provider{Type: "qwen", APIKey: "sk-test"},
+			basePath:   "/compatible-mode/v1",
+			wantPath:   "/compatible-mode/v1/chat/completions",
+			authHeader: "Authorization",
+			authValue:  "Bearer sk-test",
+		},
+		{
+			name:       "ark",
+			provider:   config.Provider{Type: "ark", APIKey: "sk-test"},
+			basePath:   "/api/v3",
+			wantPath:   "/api/v3/chat/completions",
+			authHeader: "Authorization",
+			authValue:  "Bearer sk-test",
+		},
+		{
+			name:       "gemini",
+			provider:   config.Provider{Type: "gemini", APIKey: "sk-test"},
+			wantPath:   "/v1beta/models/test-model:",
+			authHeader: "X-Goog-Api-Key",
+			authValue:  "sk-test",
+		},
+		{
+			name:     "ollama",
+			provider: config.Provider{Type: "ollama"},
+			wantPath: "/api/chat",
+		},
+	}
+
+	for _, tt := range tests {
+		for _, proxied := range []bool{false, true} {
+			if tt.wantHost != "" && !proxied {
+				// The default base_url is only reachable through the proxy
+				continue
+			}
+			name := tt.name
+			if proxied {
+				name += "/proxy"
+			}
+			t.Run(name, func(t *testing.T) {
+				server, received := newProviderStub(t)
+				providerCfg := tt.provider
+				providerCfg.Headers = map[string]string{"X-Test": "stub"}
+				for key, value := range tt.provider.Headers {
+					providerCfg.Headers[key] = value
+				}
+				providerCfg.Timeout = "10s"
+				providerCfg.BaseURL = server.URL + tt.basePath
+				wantHost := tt.wantHost
+				if proxied {
+					// Plain HTTP requests through a proxy keep the target host, the stub answers as the proxy
+					providerCfg.Proxy = server.URL
+					providerCfg.BaseURL = "http://models.example.test" + tt.basePath
+					if wantHost == "" {
+						wantHost = "models.example.test"
+					} else {
+						providerCfg.BaseURL = ""
+					}
+				}
+
+				providerType, ok := Lookup(providerCfg.Type)
+				if !ok {
+					t.Fatalf("provider type %s is not registered", providerCfg.Type)
+				}
+				ctx := context.Background()
+				m, err := providerType.New(ctx, &config.Model{Model: "test-model"}, &providerCfg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, err = m.Generate(ctx, []*schema.Message{schema.UserMessage("hello")})
+				if err == nil {
+					t.Fatal("expected the stub error")
+				}
+				if code := statusCode(err); code != http.StatusBadRequest {
+					t.Errorf("expected status code 400 from %v, got %d", err, code)
+				}
+
+				req := received()
+				if req == nil {
+					t.Fatal("no request reached the stub")
+				}
+				if !strings.HasPrefix(req.path, tt.wantPath) {
+					t.Errorf("expected path %s, got %s", tt.wantPath, req.path)
+				}
+				if proxied && req.host != wantHost {
+					t.Errorf("expected the request for %s through the proxy, got host %s", wantHost, req.host)
+				}
+				for key := range tt.wantQuery {
+					if got := req.query.Get(key); got != tt.wantQuery.Get(key) {
+						t.Errorf("expected query parameter %s=%s, got %q", key, tt.wantQuery.Get(key), got)
+					}
+				}
+				if tt.authHeader != "" && req.header.Get(tt.authHeader) != tt.authValue {
+					t.Errorf("expected header %s: %s, got %q", tt.authHeader, tt.authValue, req.header.Get(tt.authHeader))
+				}
+				want := map[string]string{"X-Test": "stub"}
+				for key, value := range tt.wantHeader {
+					want[key] = value
+				}
+				for key, value := range want {
+					if got := req.header.Get(key); got != value {
+						t.Errorf("expected header %s: %s, got %q", key, value, got)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestQianfanCredentials(t *testing.T) {
+	qianfanConfigured = nil
+	t.Cleanup(func() { qianfanConfigured = nil })
+
+	first := &config.Provider{Type: "qianfan", AccessKey: "ak-first", SecretKey: "sk-first", BaseURL: "http://qianfan.example.test"}
+	if err := configureQianfan(first); err != nil {
+		t.Fatal(err)
+	}
+	if err := configureQianfan(&config.Provider{Type: "qianfan", AccessKey: "ak-first", SecretKey: "sk-first", BaseURL: "http://qianfan.example.test"}); err != nil {
+		t.Fatalf("expected a provider with the same credentials to be accepted: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		provider config.Provider
+	}{
+		{"access key", config.Provider{Type: "qianfan", AccessKey: "ak-second", SecretKey: "sk-first", BaseURL: "http://qianfan.example.test"}},
+		{"secret key", config.Provider{Type: "qianfan", AccessKey: "ak-first", SecretKey: "sk-second", BaseURL: "http://qianfan.example.test"}},
+		{"base url", config.Provider{Type: "qianfan", AccessKey: "ak-first", SecretKey: "sk-first"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := configureQianfan(&tt.provider); err == nil {
+				t.Fatal("expected a provider with different credentials to be rejected")
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cloudwego/eino-ext/components/model/qianfan"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/tk103331/eino-cli/config"
+	"github.com/tk103331/eino-cli/logger"
+)
+
+func init() {
+	Register("qianfan", newQianfanModel)
+}
+
+// qianfanCredentials are the provider settings the Qianfan SDK keeps in its process wide config
+type qianfanCredentials struct {
+	accessKey string
+	secretKey string
+	baseURL   string
+}
+
+var (
+	qianfanMu sync.Mutex
+	// qianfanConfigured holds the credentials of the first qianfan provider that created a model
+	qianfanConfigured *qianfanCredentials
+)
+
+// configureQianfan sets the provider credentials on the SDK config.
+// Models of another qianfan provider would silently use the same credentials, so differing ones are rejected.
+func configureQianfan(providerCfg *config.Provider) error {
+	creds := qianfanCredentials{accessKey: providerCfg.AccessKey, secretKey: providerCfg.SecretKey, baseURL: providerCfg.BaseURL}
+	qianfanMu.Lock()
+	defer qianfanMu.Unlock()
+	if qianfanConfigured != nil {
+		if *qianfanConfigured != creds {
+			return fmt.Errorf("the Qianfan SDK keeps one set of credentials per process, all qianfan providers must use the same access_key, secret_key and base_url")
+		}
+		return nil
+	}
+
+	qianfanCfg := qianfan.GetQianfanSingletonConfig()
+	if creds.accessKey != "" {
+		qianfanCfg.AccessKey = creds.accessKey
+		qianfanCfg.SecretKey = creds.secretKey
+	}
+	if creds.baseURL != "" {
+		qianfanCfg.BaseURL = creds.baseURL
+	}
+	qianfanConfigured = &creds
+	return nil
+}
+
+// newQianfanModel creates Qianfan model
+func newQianfanModel(ctx context.Context, modelCfg *config.Model, providerCfg *config.Provider) (model.ToolCallingChatModel, error) {
+	if err := configureQianfan(providerCfg); err != nil {
+		return nil, err
+	}
+
+	cfg := &qianfan.ChatModelConfig{
+		Model:               modelCfg.Model,
+		MaxCompletionTokens: modelCfg.MaxTokens,
+		Temperature:         float32Ptr(modelCfg.Temperature),
+		TopP:                float32Ptr(modelCfg.TopP),
+		Stop:                modelCfg.Stop,
+		Seed:                modelCfg.Seed,
+		PresencePenalty:     modelCfg.PresencePenalty,
+		FrequencyPenalty:    modelCfg.FrequencyPenalty,
+	}
+	if providerCfg.Timeout != "" {
+		timeout, err := parseTimeout(providerCfg.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		seconds := float32(timeout.Seconds())
+		cfg.LLMRetryTimeout = &seconds
+	}
+	if providerCfg.Proxy != "" {
+		logger.Warn("MODEL", "Provider type qianfan does not support proxy, set HTTPS_PROXY instead")
+	}
+	warnIgnored(modelCfg, providerCfg.Type, "top_k", "response_format", "reasoning_effort")
+	if err := applyExtra(modelCfg, cfg); err != nil {
+		return nil, err
+	}
+
+	return qianfan.NewChatModel(ctx, cfg)
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino-ext/components/model/openai"
+	"github.com/cloudwego/eino-ext/components/model/qwen"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/tk103331/eino-cli/config"
+)
+
+func init() {
+	Register("qwen", newQwenModel)
+}
+
+// newQwenModel creates Qwen model
+func newQwenModel(ctx context.Context, modelCfg *config.Model, providerCfg *config.Provider) (model.ToolCallingChatModel, error) {
+	httpClient, err := newHTTPClient(providerCfg)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &qwen.ChatModelConfig{
+		Model:            modelCfg.Model,
+		BaseURL:          providerCfg.BaseURL,
+		APIKey:           providerCfg.APIKey,
+		HTTPClient:       httpClient,
+		MaxTokens:        modelCfg.MaxTokens,
+		Temperature:      float32Ptr(modelCfg.Temperature),
+		TopP:             float32Ptr(modelCfg.TopP),
+		Stop:             modelCfg.Stop,
+		Seed:             modelCfg.Seed,
+		PresencePenalty:  float32Ptr(modelCfg.PresencePenalty),
+		FrequencyPenalty: float32Ptr(modelCfg.FrequencyPenalty),
+	}
+	if modelCfg.ResponseFormat == responseFormatJSONObject {
+		cfg.ResponseFormat = &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject}
+	}
+	warnIgnored(modelCfg, providerCfg.Type, "top_k", "reasoning_effort")
+	if err := applyExtra(modelCfg, cfg); err != nil {
+		return nil, err
+	}
+
+	return qwen.NewChatModel(ctx, cfg)
+}
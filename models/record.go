@@ -0,0 +1,136 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/tk103331/eino-cli/logger"
+)
+
+// cassetteRecorder appends the exchanges of all models to a cassette file
+type cassetteRecorder struct {
+	mu       sync.Mutex
+	path     string
+	cassette Cassette
+}
+
+var (
+	recorderMu sync.Mutex
+	recorder   *cassetteRecorder
+)
+
+// RecordTo records every model exchange of the process into a cassette, which the mock provider can replay.
+// An existing cassette is overwritten.
+func RecordTo(path string) error {
+	r := &cassetteRecorder{path: path}
+	if err := r.cassette.Save(path); err != nil {
+		return fmt.Errorf("failed to create cassette: %w", err)
+	}
+	recorderMu.Lock()
+	defer recorderMu.Unlock()
+	recorder = r
+	return nil
+}
+
+// currentRecorder returns the recorder set by RecordTo, or nil when exchanges are not recorded
+func currentRecorder() *cassetteRecorder {
+	recorderMu.Lock()
+	defer recorderMu.Unlock()
+	return recorder
+}
+
+// add appends an interaction and saves the cassette
+func (r *cassetteRecorder) add(interaction Interaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	if err := r.cassette.Save(r.path); err != nil {
+		logger.Warn("MODEL", fmt.Sprintf("Failed to save cassette %s: %v", r.path, err))
+	}
+}
+
+// recordingModel records the exchanges of a model
+type recordingModel struct {
+	model    string
+	inner    model.ToolCallingChatModel
+	recorder *cassetteRecorder
+}
+
+// newRecordingModel wraps a model so that its exchanges are recorded
+func newRecordingModel(modelName string, inner model.ToolCallingChatModel, r *cassetteRecorder) model.ToolCallingChatModel {
+	return &recordingModel{model: modelName, inner: inner, recorder: r}
+}
+
+// Generate implements model.BaseChatModel
+func (m *recordingModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	msg, err := m.inner.Generate(ctx, input, opts...)
+	interaction := Interaction{Model: m.model, Input: lastContent(input)}
+	if err != nil {
+		interaction.Error = err.Error()
+	} else {
+		interaction.Response = fromMessage(msg)
+	}
+	m.recorder.add(interaction)
+	return msg, err
+}
+
+// Stream implements model.BaseChatModel, recording the concatenated response once the stream is finished
+func (m *recordingModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	interaction := Interaction{Model: m.model, Input: lastContent(input), Stream: true}
+	stream, err := m.inner.Stream(ctx, input, opts...)
+	if err != nil {
+		interaction.Error = err.Error()
+		m.recorder.add(interaction)
+		return nil, err
+	}
+
+	copies := stream.Copy(2)
+	go func() {
+		reader := copies[1]
+		defer reader.Close()
+		var chunks []*schema.Message
+		for {
+			chunk, err := reader.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				interaction.Error = err.Error()
+				m.recorder.add(interaction)
+				return
+			}
+			chunks = append(chunks, chunk)
+		}
+		msg, err := schema.ConcatMessages(chunks)
+		if err != nil {
+			interaction.Error = err.Error()
+		} else {
+			interaction.Response = fromMessage(msg)
+			for _, chunk := range chunks {
+				interaction.Response.Chunks = append(interaction.Response.Chunks, chunk.Content)
+			}
+		}
+		m.recorder.add(interaction)
+	}()
+	return copies[0], nil
+}
+
+// WithTools implements model.ToolCallingChatModel
+func (m *recordingModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	inner, err := m.inner.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingModel{model: m.model, inner: inner, recorder: m.recorder}, nil
+}
+
+// IsCallbacksEnabled reports whether the recorded model handles callbacks itself
+func (m *recordingModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(m.inner)
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/tk103331/eino-cli/config"
+)
+
+// Constructor creates a chat model of a provider type from the model and provider configuration
+type Constructor func(ctx context.Context, modelCfg *config.Model, providerCfg *config.Provider) (model.ToolCallingChatModel, error)
+
+// ProviderType describes a registered provider type
+type ProviderType struct {
+	Name     string
+	Required []string // Provider settings the type cannot work without, e.g. api_key
+	Uncached bool     // Responses must not be served from the response cache
+	New      Constructor
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]ProviderType)
+)
+
+// Register makes a provider type available to Factory, usually from the init function of the file implementing it.
+// Programs embedding eino-cli can register their own provider types before loading the configuration.
+// Type names are case-insensitive; registering the same type twice panics.
+func Register(providerType string, constructor Constructor, required ...string) {
+	register(ProviderType{Name: providerType, Required: required, New: constructor})
+}
+
+// RegisterUncached registers a provider type whose responses are never cached, such as scripted mock responses
+func RegisterUncached(providerType string, constructor Constructor, required ...string) {
+	register(ProviderType{Name: providerType, Required: required, Uncached: true, New: constructor})
+}
+
+func register(t ProviderType) {
+	t.Name = strings.ToLower(t.Name)
+	if t.Name == "" || t.New == nil {
+		panic("models: Register requires a provider type and a constructor")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[t.Name]; exists {
+		panic(fmt.Sprintf("models: provider type %s registered twice", t.Name))
+	}
+	registry[t.Name] = t
+}
+
+// Lookup returns a registered provider type
+func Lookup(providerType string) (ProviderType, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	t, ok := registry[strings.ToLower(providerType)]
+	return t, ok
+}
+
+// ProviderTypes returns the registered provider types in alphabetical order
+func ProviderTypes() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	types := make([]string, 0, len(registry))
+	for t := range registry {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
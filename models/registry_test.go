@@ -0,0 +1,51 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tk103331/eino-cli/config"
+)
+
+func TestValidateProviderRequired(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider config.Provider
+		wantErr  string // Expected diagnostic substring, empty for none
+	}{
+		{"mock with cassette", config.Provider{Type: "mock", Cassette: "testdata/chat.json"}, ""},
+		{"mock without cassette", config.Provider{Type: "mock"}, "requires cassette"},
+		{"type is case-insensitive", config.Provider{Type: "Mock"}, "requires cassette"},
+		{"unknown type", config.Provider{Type: "nope"}, "unsupported provider type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Providers: map[string]config.Provider{"test": tt.provider}}
+			var diags config.Diagnostics
+			ValidateProvider(cfg, "test", &diags)
+			if tt.wantErr == "" {
+				if len(diags) > 0 {
+					t.Fatalf("expected no diagnostics, got %v", diags)
+				}
+				return
+			}
+			if len(diags) != 1 || !strings.Contains(diags[0].Message, tt.wantErr) {
+				t.Fatalf("expected one diagnostic containing %q, got %v", tt.wantErr, diags)
+			}
+		})
+	}
+}
+
+func TestHasSetting(t *testing.T) {
+	providerCfg := config.Provider{Type: "gemini", APIKeys: []config.APIKey{{Key: "sk-test"}}}
+	if !hasSetting(providerCfg, "api_key") {
+		t.Error("expected api_keys to stand in for api_key")
+	}
+	if hasSetting(providerCfg, "base_url") {
+		t.Error("expected an empty base_url to be missing")
+	}
+	providerCfg.BaseURL = "http://localhost"
+	if !hasSetting(providerCfg, "base_url") {
+		t.Error("expected base_url to be set")
+	}
+}
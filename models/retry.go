@@ -0,0 +1,197 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tk103331/eino-cli/config"
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultBackoff     = time.Second
+	defaultMaxBackoff  = 30 * time.Second
+)
+
+var defaultRetryOn = []string{"429", "5xx", "timeout"}
+
+// statusPattern finds HTTP status codes in error messages of provider SDKs without a typed error
+var statusPattern = regexp.MustCompile(`(?i)status(?:\s*code)?\s*[:=]?\s*([1-5]\d\d)\b|\b([1-5]\d\d)\s+(?:too many requests|unauthorized|forbidden|internal server error|bad gateway|service unavailable|gateway timeout)`)
+
+// retryPolicy is the parsed form of config.RetryPolicy
+type retryPolicy struct {
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	retryOn     []string
+}
+
+// newRetryPolicy parses a retry policy; a nil policy makes a single attempt
+func newRetryPolicy(cfg *config.RetryPolicy) (*retryPolicy, error) {
+	if cfg == nil {
+		return &retryPolicy{maxAttempts: 1}, nil
+	}
+	policy := &retryPolicy{
+		maxAttempts: cfg.MaxAttempts,
+		backoff:     defaultBackoff,
+		maxBackoff:  defaultMaxBackoff,
+		retryOn:     cfg.RetryOn,
+	}
+	if policy.maxAttempts <= 0 {
+		policy.maxAttempts = defaultMaxAttempts
+	}
+	if len(policy.retryOn) == 0 {
+		policy.retryOn = defaultRetryOn
+	}
+	if cfg.Backoff != "" {
+		d, err := time.ParseDuration(cfg.Backoff)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry backoff %q: %w", cfg.Backoff, err)
+		}
+		policy.backoff = d
+	}
+	if cfg.MaxBackoff != "" {
+		d, err := time.ParseDuration(cfg.MaxBackoff)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry max_backoff %q: %w", cfg.MaxBackoff, err)
+		}
+		policy.maxBackoff = d
+	}
+	for _, cond := range policy.retryOn {
+		if !validRetryCondition(cond) {
+			return nil, fmt.Errorf("invalid retry_on condition %q, must be a status code, a class such as 5xx, or timeout", cond)
+		}
+	}
+	return policy, nil
+}
+
+// validRetryCondition checks a retry_on entry
+func validRetryCondition(cond string) bool {
+	cond = strings.ToLower(strings.TrimSpace(cond))
+	if cond == "timeout" {
+		return true
+	}
+	if len(cond) == 3 && strings.HasSuffix(cond, "xx") {
+		return cond[0] >= '1' && cond[0] <= '5'
+	}
+	code, err := strconv.Atoi(cond)
+	return err == nil && code >= 100 && code <= 599
+}
+
+// delay returns the backoff before the given retry (1 for the first retry)
+func (p *retryPolicy) delay(retry int) time.Duration {
+	d := p.backoff
+	for i := 1; i < retry && d < p.maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, p.maxBackoff)
+}
+
+// shouldRetry reports whether an error matches the retry_on conditions
+func (p *retryPolicy) shouldRetry(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	code := statusCode(err)
+	timeout := isTimeout(err)
+	for _, cond := range p.retryOn {
+		cond = strings.ToLower(strings.TrimSpace(cond))
+		switch {
+		case cond == "timeout":
+			if timeout {
+				return true
+			}
+		case strings.HasSuffix(cond, "xx"):
+			if code/100 == int(cond[0]-'0') {
+				return true
+			}
+		default:
+			if c, err := strconv.Atoi(cond); err == nil && c == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wait sleeps before a retry, returning early if the context is done
+func (p *retryPolicy) wait(ctx context.Context, retry int) error {
+	timer := time.NewTimer(p.delay(retry))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// StatusCodeFunc returns the HTTP status code carried by an error of a provider SDK, or 0 if it carries none
+type StatusCodeFunc func(err error) int
+
+var (
+	statusCodeMu    sync.RWMutex
+	statusCodeFuncs []StatusCodeFunc
+)
+
+// RegisterStatusCode makes retries and fallbacks understand the error types of a provider SDK,
+// usually from the init function of the file implementing the provider type.
+func RegisterStatusCode(f StatusCodeFunc) {
+	statusCodeMu.Lock()
+	defer statusCodeMu.Unlock()
+	statusCodeFuncs = append(statusCodeFuncs, f)
+}
+
+// errorStatus returns the status code of the first error of type T in the tree of err, for use in a StatusCodeFunc
+func errorStatus[T error](err error, code func(T) int) int {
+	var target T
+	if errors.As(err, &target) {
+		return code(target)
+	}
+	return 0
+}
+
+// statusCode extracts the HTTP status code of a provider error, or 0 if there is none.
+// The registered error types are matched with errors.As, which also looks into joined errors.
+// As a last resort the message is searched, for SDKs that only report the status as text.
+func statusCode(err error) int {
+	statusCodeMu.RLock()
+	funcs := statusCodeFuncs
+	statusCodeMu.RUnlock()
+	for _, f := range funcs {
+		if code := f(err); code >= 100 && code <= 599 {
+			return code
+		}
+	}
+	if m := statusPattern.FindStringSubmatch(err.Error()); m != nil {
+		code, _ := strconv.Atoi(m[1] + m[2])
+		return code
+	}
+	return 0
+}
+
+// isClientError reports whether a status code rejects the request itself, rate limits and request timeouts excluded
+func isClientError(code int) bool {
+	return code/100 == 4 && code != http.StatusRequestTimeout && code != http.StatusTooManyRequests
+}
+
+// isTimeout reports whether an error is caused by a timeout
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "timeout") || strings.Contains(msg, "timed out")
+}
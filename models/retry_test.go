@@ -0,0 +1,73 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/tk103331/eino-cli/config"
+)
+
+// testStatusError is a typed provider error carrying its status code
+type testStatusError struct {
+	code int
+}
+
+func (e *testStatusError) Error() string { return "request failed" }
+
+func init() {
+	RegisterStatusCode(func(err error) int {
+		return errorStatus(err, func(e *testStatusError) int { return e.code })
+	})
+}
+
+func TestStatusCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"typed", &testStatusError{code: 429}, 429},
+		{"wrapped", fmt.Errorf("stream failed: %w", &testStatusError{code: 503}), 503},
+		{"joined", errors.Join(io.EOF, fmt.Errorf("attempt 2: %w", &testStatusError{code: 502})), 502},
+		{"multiple %w", fmt.Errorf("%w after %w", io.ErrUnexpectedEOF, &testStatusError{code: 500}), 500},
+		{"typed over message", fmt.Errorf("status code: 500: %w", &testStatusError{code: 404}), 404},
+		{"message", errors.New("error, status code: 503, message: overloaded"), 503},
+		{"joined message", errors.Join(io.EOF, errors.New("429 Too Many Requests")), 429},
+		{"none", errors.New("connection reset by peer"), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statusCode(tt.err); got != tt.want {
+				t.Errorf("statusCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	policy, err := newRetryPolicy(&config.RetryPolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"rate limited", &testStatusError{code: 429}, true},
+		{"joined server error", errors.Join(io.EOF, &testStatusError{code: 503}), true},
+		{"client error", &testStatusError{code: 400}, false},
+		{"timeout", context.DeadlineExceeded, true},
+		{"other", io.EOF, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.shouldRetry(context.Background(), tt.err); got != tt.want {
+				t.Errorf("shouldRetry(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+
+	"github.com/tk103331/eino-cli/config"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidateProvider checks that the provider type is supported without creating a model
+func ValidateProvider(cfg *config.Config, name string, diags *config.Diagnostics) {
+	providerCfg := cfg.Providers[name]
+	if providerCfg.Type == "" {
+		// Reported by config.Validate
+		return
+	}
+	providerType, ok := Lookup(providerCfg.Type)
+	if !ok {
+		diags.Add(cfg, []string{"providers", name, "type"}, "unsupported provider type: %s", providerCfg.Type)
+	}
+	for _, key := range providerType.Required {
+		if !hasSetting(providerCfg, key) {
+			diags.Add(cfg, []string{"providers", name}, "provider %s of type %s requires %s", name, providerType.Name, key)
+		}
+	}
+	validateKeys(cfg, name, diags)
+	validateConnection(cfg, name, diags)
+}
+
+// validateConnection checks the credentials, timeout and proxy settings of a provider
+func validateConnection(cfg *config.Config, name string, diags *config.Diagnostics) {
+	providerCfg := cfg.Providers[name]
+	if (providerCfg.AccessKey == "") != (providerCfg.SecretKey == "") {
+		diags.Add(cfg, []string{"providers", name}, "provider %s must set both access_key and secret_key", name)
+	}
+	if providerCfg.Timeout != "" {
+		if _, err := parseTimeout(providerCfg.Timeout); err != nil {
+			diags.Add(cfg, []string{"providers", name, "timeout"}, "provider %s: %v", name, err)
+		}
+	}
+	if providerCfg.Proxy != "" {
+		if _, err := parseProxy(providerCfg.Proxy); err != nil {
+			diags.Add(cfg, []string{"providers", name, "proxy"}, "provider %s: %v", name, err)
+		}
+	}
+}
+
+// hasSetting reports whether a provider sets the setting of the given YAML name, api_keys stands in for api_key
+func hasSetting(providerCfg config.Provider, key string) bool {
+	if key == "api_key" && len(providerCfg.APIKeys) > 0 {
+		return true
+	}
+	// Empty settings are omitted from the YAML form
+	data, err := yaml.Marshal(providerCfg)
+	if err != nil {
+		return false
+	}
+	var settings map[string]any
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return false
+	}
+	_, ok := settings[key]
+	return ok
+}
+
+// validateKeys checks the api_keys settings of a provider
+func validateKeys(cfg *config.Config, name string, diags *config.Diagnostics) {
+	providerCfg := cfg.Providers[name]
+	switch providerCfg.KeySelection {
+	case "", keySelectionRoundRobin, keySelectionRandom, keySelectionWeighted:
+	default:
+		diags.Add(cfg, []string{"providers", name, "key_selection"}, "unsupported key_selection: %s, must be round_robin, random or weighted", providerCfg.KeySelection)
+	}
+	if providerCfg.KeyCooldown != "" {
+		if _, err := time.ParseDuration(providerCfg.KeyCooldown); err != nil {
+			diags.Add(cfg, []string{"providers", name, "key_cooldown"}, "invalid key_cooldown %q: %v", providerCfg.KeyCooldown, err)
+		}
+	}
+	for i, key := range providerCfg.APIKeys {
+		if key.Key == "" {
+			diags.Add(cfg, []string{"providers", name, "api_keys"}, "api_keys[%d] of provider %s is empty", i, name)
+		}
+	}
+}
+
+// ValidateModel checks the retry policy and sampling options of a model without creating it
+func ValidateModel(cfg *config.Config, name string, diags *config.Diagnostics) {
+	modelCfg := cfg.Models[name]
+	if _, err := newRetryPolicy(modelCfg.Retry); err != nil {
+		diags.Add(cfg, []string{"models", name, "retry"}, "model %q: %v", name, err)
+	}
+	switch modelCfg.ResponseFormat {
+	case "", responseFormatText, responseFormatJSONObject:
+	default:
+		diags.Add(cfg, []string{"models", name, "response_format"}, "model %q: unsupported response_format: %s, must be text or json_object", name, modelCfg.ResponseFormat)
+	}
+	if _, ok := thinkingBudgets[modelCfg.ReasoningEffort]; modelCfg.ReasoningEffort != "" && !ok {
+		diags.Add(cfg, []string{"models", name, "reasoning_effort"}, "model %q: unsupported reasoning_effort: %s, must be low, medium or high", name, modelCfg.ReasoningEffort)
+	}
+}
+
+// ValidateCache checks the response cache settings
+func ValidateCache(cfg *config.Config, diags *config.Diagnostics) {
+	if cfg.Settings.Cache == nil || cfg.Settings.Cache.TTL == "" {
+		return
+	}
+	if _, err := time.ParseDuration(cfg.Settings.Cache.TTL); err != nil {
+		diags.Add(cfg, []string{"settings", "cache", "ttl"}, "invalid cache ttl %q: %v", cfg.Settings.Cache.TTL, err)
+	}
+}
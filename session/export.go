@@ -0,0 +1,81 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Export writes the session in the given format ("json" or "markdown")
+func Export(w io.Writer, sess *Session, format string) error {
+	switch strings.ToLower(format) {
+	case "", "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(sess)
+	case "markdown", "md":
+		_, err := io.WriteString(w, toMarkdown(sess))
+		return err
+	default:
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// toMarkdown renders the session as a markdown transcript
+func toMarkdown(sess *Session) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# Session %s\n\n", sess.ID)
+	if sess.Name != "" {
+		label := "Agent"
+		if sess.Kind == KindChat {
+			label = "Chat"
+		}
+		fmt.Fprintf(&sb, "- %s: %s\n", label, sess.Name)
+	}
+	if sess.Model != "" {
+		fmt.Fprintf(&sb, "- Model: %s\n", sess.Model)
+	}
+	fmt.Fprintf(&sb, "- Created: %s\n", sess.CreatedAt.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&sb, "- Updated: %s\n\n", sess.UpdatedAt.Format("2006-01-02 15:04:05"))
+
+	for _, msg := range sess.Messages {
+		switch msg.Role {
+		case schema.User:
+			fmt.Fprintf(&sb, "## User\n\n%s\n\n", msg.Content)
+		case schema.Assistant:
+			if msg.Content != "" {
+				fmt.Fprintf(&sb, "## Assistant\n\n%s\n\n", msg.Content)
+			}
+			for _, tc := range msg.ToolCalls {
+				fmt.Fprintf(&sb, "> Tool call `%s`: `%s`\n\n", tc.Function.Name, tc.Function.Arguments)
+			}
+		case schema.Tool:
+			fmt.Fprintf(&sb, "## Tool %s\n\n```\n%s\n```\n\n", msg.ToolName, msg.Content)
+		case schema.System:
+			fmt.Fprintf(&sb, "## System\n\n%s\n\n", msg.Content)
+		}
+	}
+
+	if len(sess.ToolCalls) > 0 {
+		sb.WriteString("## Tool Calls\n\n")
+		sb.WriteString("| Tool | Started | Duration | Status |\n")
+		sb.WriteString("|------|---------|----------|--------|\n")
+		for _, tc := range sess.ToolCalls {
+			status := "ok"
+			if tc.Error != "" {
+				status = "error: " + tc.Error
+			}
+			duration := ""
+			if !tc.EndedAt.IsZero() {
+				duration = tc.EndedAt.Sub(tc.StartedAt).String()
+			}
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", tc.Name, tc.StartedAt.Format("15:04:05"), duration, status)
+		}
+	}
+
+	return sb.String()
+}
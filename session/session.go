@@ -0,0 +1,137 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Session kinds
+const (
+	KindAgent = "agent"
+	KindChat  = "chat"
+)
+
+// ToolCall represents a recorded tool invocation within a session
+type ToolCall struct {
+	Name      string    `json:"name"`
+	Arguments string    `json:"arguments,omitempty"`
+	Result    string    `json:"result,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at,omitempty"`
+}
+
+// Session represents a persisted conversation
+type Session struct {
+	mu sync.Mutex
+
+	ID        string            `json:"id"`
+	Kind      string            `json:"kind"`           // "agent" or "chat"
+	Name      string            `json:"name,omitempty"` // Agent name or chat preset name
+	Model     string            `json:"model,omitempty"`
+	System    string            `json:"system,omitempty"` // System prompt (only used for chat sessions)
+	Tools     []string          `json:"tools,omitempty"`  // Tool names (only used for chat sessions)
+	Messages  []*schema.Message `json:"messages"`
+	ToolCalls []ToolCall        `json:"tool_calls,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// New creates a new session with a generated ID
+func New(kind, name, model string) *Session {
+	now := time.Now()
+	return &Session{
+		ID:        newID(now),
+		Kind:      kind,
+		Name:      name,
+		Model:     model,
+		Messages:  []*schema.Message{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// SetMessages replaces the session messages with the current conversation history
+func (s *Session) SetMessages(messages []*schema.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Messages = messages
+	s.UpdatedAt = time.Now()
+}
+
+// History returns a copy of the session messages
+func (s *Session) History() []*schema.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := make([]*schema.Message, len(s.Messages))
+	copy(messages, s.Messages)
+	return messages
+}
+
+// StartToolCall records the start of a tool invocation
+func (s *Session) StartToolCall(name, arguments string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ToolCalls = append(s.ToolCalls, ToolCall{
+		Name:      name,
+		Arguments: arguments,
+		StartedAt: time.Now(),
+	})
+}
+
+// EndToolCall completes the most recent unfinished invocation of the named tool
+func (s *Session) EndToolCall(name, result, errMsg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := len(s.ToolCalls) - 1; i >= 0; i-- {
+		if s.ToolCalls[i].Name == name && s.ToolCalls[i].EndedAt.IsZero() {
+			s.ToolCalls[i].Result = result
+			s.ToolCalls[i].Error = errMsg
+			s.ToolCalls[i].EndedAt = time.Now()
+			return
+		}
+	}
+
+	// No matching start was recorded, store a completed entry
+	now := time.Now()
+	s.ToolCalls = append(s.ToolCalls, ToolCall{
+		Name:      name,
+		Result:    result,
+		Error:     errMsg,
+		StartedAt: now,
+		EndedAt:   now,
+	})
+}
+
+// Title returns a short description of the session, based on the first user message
+func (s *Session) Title() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, msg := range s.Messages {
+		if msg.Role == schema.User {
+			title := []rune(strings.Join(strings.Fields(msg.Content), " "))
+			if len(title) > 60 {
+				return string(title[:57]) + "..."
+			}
+			return string(title)
+		}
+	}
+	return ""
+}
+
+// newID generates a sortable session ID
+func newID(t time.Time) string {
+	buf := make([]byte, 3)
+	_, _ = rand.Read(buf)
+	return t.Format("20060102-150405") + "-" + hex.EncodeToString(buf)
+}
@@ -0,0 +1,129 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrSessionNotFound session does not exist in the store
+var ErrSessionNotFound = errors.New("session not found")
+
+// Store persists sessions as JSON files in a directory
+type Store struct {
+	dir string
+}
+
+// DefaultDir returns the default session directory (~/.eino-cli/sessions)
+func DefaultDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return filepath.Join(homeDir, ".eino-cli", "sessions")
+}
+
+// NewStore creates a new Store, uses DefaultDir when dir is empty
+func NewStore(dir string) *Store {
+	if dir == "" {
+		dir = DefaultDir()
+	}
+	return &Store{dir: dir}
+}
+
+// Dir returns the directory sessions are stored in
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Save writes the session to disk
+func (s *Store) Save(sess *Session) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	sess.mu.Lock()
+	data, err := json.MarshalIndent(sess, "", "  ")
+	sess.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	// Write to a temporary file first so an interrupted save never corrupts the session
+	path := s.path(sess.ID)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	return nil
+}
+
+// Load reads a session from disk
+func (s *Store) Load(id string) (*Session, error) {
+	data, err := os.ReadFile(s.path(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	var sess Session
+	if err := json.Unmarshal(data, &sess); err != nil {
+		return nil, fmt.Errorf("failed to parse session file: %w", err)
+	}
+	return &sess, nil
+}
+
+// List returns all stored sessions, most recently updated first
+func (s *Store) List() ([]*Session, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read session directory: %w", err)
+	}
+
+	var sessions []*Session
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		sess, err := s.Load(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			// Skip unreadable files instead of failing the whole listing
+			continue
+		}
+		sessions = append(sessions, sess)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].UpdatedAt.After(sessions[j].UpdatedAt)
+	})
+	return sessions, nil
+}
+
+// Delete removes a session from disk
+func (s *Store) Delete(id string) error {
+	if err := os.Remove(s.path(id)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+		}
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// path returns the file path for a session ID
+func (s *Store) path(id string) string {
+	// Only use the base name so IDs cannot escape the session directory
+	return filepath.Join(s.dir, filepath.Base(id)+".json")
+}
@@ -0,0 +1,150 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestStoreSaveLoad(t *testing.T) {
+	store := NewStore(t.TempDir())
+	sess := New(KindChat, "", "gpt-4o")
+	sess.System = "Be brief"
+	sess.Tools = []string{"search"}
+	sess.StartToolCall("search", `{"q":"go"}`)
+	sess.EndToolCall("search", "results", "")
+	sess.SetMessages([]*schema.Message{
+		schema.UserMessage("find go"),
+		{Role: schema.Assistant, ToolCalls: []schema.ToolCall{{ID: "call_1", Function: schema.FunctionCall{Name: "search", Arguments: `{"q":"go"}`}}}},
+		schema.ToolMessage("results", "call_1", schema.WithToolName("search")),
+		schema.AssistantMessage("Go is a language", nil),
+	})
+	if err := store.Save(sess); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := store.Load(sess.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Kind != KindChat || loaded.Model != "gpt-4o" || loaded.System != "Be brief" || strings.Join(loaded.Tools, ",") != "search" {
+		t.Errorf("expected the session settings to be restored, got %+v", loaded)
+	}
+	history := loaded.History()
+	if len(history) != 4 {
+		t.Fatalf("expected 4 messages, got %d", len(history))
+	}
+	if calls := history[1].ToolCalls; len(calls) != 1 || calls[0].ID != "call_1" || calls[0].Function.Name != "search" {
+		t.Errorf("expected the tool call of the assistant, got %+v", calls)
+	}
+	if history[2].ToolCallID != "call_1" || history[2].ToolName != "search" {
+		t.Errorf("expected the tool result to keep its call, got %+v", history[2])
+	}
+	if len(loaded.ToolCalls) != 1 || loaded.ToolCalls[0].Result != "results" || loaded.ToolCalls[0].EndedAt.IsZero() {
+		t.Errorf("expected the finished tool call record, got %+v", loaded.ToolCalls)
+	}
+	if loaded.Title() != "find go" {
+		t.Errorf("expected title %q, got %q", "find go", loaded.Title())
+	}
+}
+
+func TestStoreListDelete(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "sessions")
+	store := NewStore(dir)
+	older, newer := New(KindAgent, "helper", ""), New(KindChat, "", "")
+	older.UpdatedAt = time.Now().Add(-time.Hour)
+	for _, sess := range []*Session{older, newer} {
+		if err := store.Save(sess); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Unreadable files are skipped
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != newer.ID || sessions[1].ID != older.ID {
+		t.Fatalf("expected the sessions newest first, got %d sessions", len(sessions))
+	}
+
+	if err := store.Delete(older.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "outside.json"), []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"deleted", older.ID},
+		{"missing", "20240101-000000-abcdef"},
+		{"outside the directory", "../outside"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := store.Load(tt.id); !errors.Is(err, ErrSessionNotFound) {
+				t.Errorf("expected ErrSessionNotFound, got %v", err)
+			}
+		})
+	}
+	if err := store.Delete(older.ID); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("expected deleting twice to fail with ErrSessionNotFound, got %v", err)
+	}
+
+	// Listing a missing directory is empty
+	if sessions, err := NewStore(filepath.Join(dir, "missing")).List(); err != nil || len(sessions) != 0 {
+		t.Errorf("expected no sessions, got %d and %v", len(sessions), err)
+	}
+}
+
+func TestExport(t *testing.T) {
+	sess := New(KindAgent, "helper", "gpt-4o")
+	sess.SetMessages([]*schema.Message{
+		schema.UserMessage("list files"),
+		{Role: schema.Assistant, ToolCalls: []schema.ToolCall{{Function: schema.FunctionCall{Name: "ls", Arguments: `{}`}}}},
+		schema.ToolMessage("a.txt", "", schema.WithToolName("ls")),
+		schema.AssistantMessage("There is a.txt", nil),
+	})
+	sess.EndToolCall("ls", "a.txt", "")
+
+	tests := []struct {
+		format  string
+		want    []string
+		wantErr bool
+	}{
+		{format: "markdown", want: []string{"- Agent: helper", "## User\n\nlist files", "> Tool call `ls`: `{}`", "## Tool ls\n\n```\na.txt\n```", "## Assistant\n\nThere is a.txt", "| ls |"}},
+		{format: "JSON", want: []string{`"kind": "agent"`, `"content": "There is a.txt"`}},
+		{format: "html", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var sb strings.Builder
+			err := Export(&sb, sess, tt.format)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an unsupported format error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(sb.String(), want) {
+					t.Errorf("expected %q in the export:\n%s", want, sb.String())
+				}
+			}
+		})
+	}
+}
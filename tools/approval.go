@@ -0,0 +1,275 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+	"github.com/tk103331/eino-cli/config"
+	"github.com/tk103331/eino-cli/logger"
+)
+
+// Approval policies of tools and MCP servers
+const (
+	ApprovalAlways  = "always"  // Run every call without asking
+	ApprovalNever   = "never"   // Deny every call
+	ApprovalAsk     = "ask"     // Ask before every call
+	ApprovalPattern = "pattern" // Decide by approval_rules, ask when no rule matches
+)
+
+// ApprovalRequest is a tool call waiting for a human decision
+type ApprovalRequest struct {
+	Tool      string
+	Arguments string
+}
+
+// ApprovalDecision is the answer to an ApprovalRequest
+type ApprovalDecision struct {
+	Approved  bool
+	Arguments string // Edited arguments, empty keeps the requested ones
+	Reason    string
+}
+
+// Approver asks a human about a tool call and blocks until the decision is made
+type Approver func(ctx context.Context, req ApprovalRequest) ApprovalDecision
+
+var (
+	approverMu sync.RWMutex
+	approver   Approver
+)
+
+// SetApprover sets the approver asked by the ask and pattern policies.
+// Without an approver the process is non-interactive and such calls are denied.
+func SetApprover(a Approver) {
+	approverMu.Lock()
+	defer approverMu.Unlock()
+	approver = a
+}
+
+func getApprover() Approver {
+	approverMu.RLock()
+	defer approverMu.RUnlock()
+	return approver
+}
+
+// approvalPolicy is a compiled approval policy
+type approvalPolicy struct {
+	mode  string
+	allow []*regexp.Regexp
+	deny  []*regexp.Regexp
+}
+
+// newApprovalPolicy compiles the approval settings of a tool or MCP server
+func newApprovalPolicy(mode string, rules *config.ApprovalRules) (*approvalPolicy, error) {
+	policy := &approvalPolicy{mode: strings.ToLower(mode)}
+	switch policy.mode {
+	case "":
+		policy.mode = ApprovalAlways
+	case ApprovalAlways, ApprovalNever, ApprovalAsk:
+	case ApprovalPattern:
+		if rules == nil || len(rules.Allow)+len(rules.Deny) == 0 {
+			return nil, fmt.Errorf("approval pattern requires approval_rules")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported approval %q (must be always, never, ask or pattern)", mode)
+	}
+	if rules != nil {
+		for _, list := range []struct {
+			patterns []string
+			target   *[]*regexp.Regexp
+		}{{rules.Allow, &policy.allow}, {rules.Deny, &policy.deny}} {
+			for _, pattern := range list.patterns {
+				re, err := regexp.Compile(pattern)
+				if err != nil {
+					return nil, fmt.Errorf("invalid approval rule %q: %w", pattern, err)
+				}
+				*list.target = append(*list.target, re)
+			}
+		}
+	}
+	return policy, nil
+}
+
+// ValidateApproval checks approval settings without wrapping a tool
+func ValidateApproval(mode string, rules *config.ApprovalRules) error {
+	_, err := newApprovalPolicy(mode, rules)
+	return err
+}
+
+// WithApproval wraps a tool so that its calls are decided by the approval policy first.
+// Tools that are always approved are returned unchanged.
+func WithApproval(name, mode string, rules *config.ApprovalRules, t tool.InvokableTool) (tool.InvokableTool, error) {
+	policy, err := newApprovalPolicy(mode, rules)
+	if err != nil {
+		return nil, fmt.Errorf("tool %s: %w", name, err)
+	}
+	if policy.mode == ApprovalAlways {
+		return t, nil
+	}
+	wrapped := &approvalTool{InvokableTool: t, name: name, policy: policy}
+	if st, ok := t.(tool.StreamableTool); ok {
+		return &streamableApprovalTool{approvalTool: wrapped, streamable: st}, nil
+	}
+	return wrapped, nil
+}
+
+// approvalTool asks for approval before running the wrapped tool
+type approvalTool struct {
+	tool.InvokableTool
+	name   string
+	policy *approvalPolicy
+}
+
+// InvokableRun implements tool.InvokableTool. Denied calls are reported to the model as the tool result,
+// so that it can continue without the tool.
+func (t *approvalTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	decision := t.decide(ctx, argumentsInJSON)
+	if !decision.Approved {
+		return fmt.Sprintf("Tool call was denied: %s", decision.Reason), nil
+	}
+	if decision.Arguments != "" {
+		argumentsInJSON = decision.Arguments
+	}
+	return t.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
+}
+
+// streamableApprovalTool keeps the streaming of a wrapped tool that supports it
+type streamableApprovalTool struct {
+	*approvalTool
+	streamable tool.StreamableTool
+}
+
+// StreamableRun implements tool.StreamableTool, denied calls are reported like in InvokableRun
+func (t *streamableApprovalTool) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (*schema.StreamReader[string], error) {
+	decision := t.decide(ctx, argumentsInJSON)
+	if !decision.Approved {
+		return schema.StreamReaderFromArray([]string{fmt.Sprintf("Tool call was denied: %s", decision.Reason)}), nil
+	}
+	if decision.Arguments != "" {
+		argumentsInJSON = decision.Arguments
+	}
+	return t.streamable.StreamableRun(ctx, argumentsInJSON, opts...)
+}
+
+// decide applies the policy, asks the approver if needed and writes the decision to the audit log
+func (t *approvalTool) decide(ctx context.Context, arguments string) ApprovalDecision {
+	entry := auditEntry{Time: time.Now(), Tool: t.name, Arguments: arguments, Policy: t.policy.mode, DecidedBy: "policy"}
+	decision := t.policy.evaluate(arguments)
+	if decision == nil {
+		if a := getApprover(); a != nil {
+			d := a(ctx, ApprovalRequest{Tool: t.name, Arguments: arguments})
+			decision = &d
+			entry.DecidedBy = "user"
+		} else {
+			decision = &ApprovalDecision{Reason: "approval required but no interactive terminal is available"}
+			entry.DecidedBy = "non-interactive"
+		}
+	}
+	if decision.Arguments == arguments {
+		decision.Arguments = ""
+	}
+	if decision.Reason == "" && !decision.Approved {
+		decision.Reason = "denied by user"
+	}
+
+	entry.Approved = decision.Approved
+	entry.EditedArguments = decision.Arguments
+	entry.Reason = decision.Reason
+	writeAudit(entry)
+	return *decision
+}
+
+// evaluate decides a call by the policy alone, nil means the approver must be asked
+func (p *approvalPolicy) evaluate(arguments string) *ApprovalDecision {
+	switch p.mode {
+	case ApprovalAlways:
+		return &ApprovalDecision{Approved: true}
+	case ApprovalNever:
+		return &ApprovalDecision{Reason: "tool calls are not allowed by policy"}
+	case ApprovalPattern:
+		arguments = canonicalArguments(arguments)
+		for _, re := range p.deny {
+			if re.MatchString(arguments) {
+				return &ApprovalDecision{Reason: fmt.Sprintf("arguments match deny rule %q", re.String())}
+			}
+		}
+		for _, re := range p.allow {
+			if re.MatchString(arguments) {
+				return &ApprovalDecision{Approved: true, Reason: fmt.Sprintf("arguments match allow rule %q", re.String())}
+			}
+		}
+	}
+	return nil
+}
+
+// canonicalArguments returns the JSON arguments compacted with sorted keys and without escapes,
+// so that rules see the same text however the model formatted the call. Invalid JSON is returned unchanged.
+func canonicalArguments(arguments string) string {
+	decoder := json.NewDecoder(strings.NewReader(arguments))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return arguments
+	}
+	var buf strings.Builder
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return arguments
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// auditEntry is one line of the approval audit log
+type auditEntry struct {
+	Time            time.Time `json:"time"`
+	Tool            string    `json:"tool"`
+	Arguments       string    `json:"arguments"`
+	Policy          string    `json:"policy"`
+	Approved        bool      `json:"approved"`
+	DecidedBy       string    `json:"decided_by"` // policy, user or non-interactive
+	EditedArguments string    `json:"edited_arguments,omitempty"`
+	Reason          string    `json:"reason,omitempty"`
+}
+
+var auditMu sync.Mutex
+
+// AuditLogPath returns the file where approval decisions are appended, ~/.eino-cli/approvals.log
+func AuditLogPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return filepath.Join(homeDir, ".eino-cli", "approvals.log")
+}
+
+// writeAudit appends a decision to the audit log as one JSON line
+func writeAudit(entry auditEntry) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+
+	data, err := json.Marshal(entry)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(AuditLogPath()), 0755)
+	}
+	if err == nil {
+		var f *os.File
+		if f, err = os.OpenFile(AuditLogPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600); err == nil {
+			_, err = f.Write(append(data, '\n'))
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}
+	}
+	if err != nil {
+		logger.Warn("TOOL", fmt.Sprintf("Failed to write approval audit log: %v", err))
+	}
+}
@@ -0,0 +1,192 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+	"github.com/tk103331/eino-cli/config"
+)
+
+// echoTool answers with the call arguments
+type echoTool struct{}
+
+func (echoTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "echo", Desc: "Echoes the arguments"}, nil
+}
+
+func (echoTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	return "ran " + argumentsInJSON, nil
+}
+
+// readAudit returns the entries of the audit log
+func readAudit(t *testing.T) []auditEntry {
+	t.Helper()
+	f, err := os.Open(AuditLogPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var entries []auditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry auditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestWithApproval(t *testing.T) {
+	rules := &config.ApprovalRules{
+		Allow: []string{`"subcommand":"(status|log)"`, `"path":"/tmp/`},
+		Deny:  []string{`push|reset`, `"force":true`},
+	}
+	approve := func(ctx context.Context, req ApprovalRequest) ApprovalDecision {
+		return ApprovalDecision{Approved: true}
+	}
+	deny := func(ctx context.Context, req ApprovalRequest) ApprovalDecision {
+		return ApprovalDecision{}
+	}
+	edit := func(ctx context.Context, req ApprovalRequest) ApprovalDecision {
+		return ApprovalDecision{Approved: true, Arguments: `{"subcommand":"status"}`}
+	}
+
+	tests := []struct {
+		name          string
+		mode          string
+		approver      Approver
+		arguments     string
+		want          string // Tool result
+		wantDecidedBy string // Empty when no decision is logged
+	}{
+		{name: "always", mode: "", arguments: `{"subcommand":"push"}`, want: `ran {"subcommand":"push"}`},
+		{name: "never", mode: "never", approver: approve, arguments: `{}`, want: "Tool call was denied: tool calls are not allowed by policy", wantDecidedBy: "policy"},
+		{name: "ask approved", mode: "ask", approver: approve, arguments: `{"subcommand":"push"}`, want: `ran {"subcommand":"push"}`, wantDecidedBy: "user"},
+		{name: "ask denied", mode: "ASK", approver: deny, arguments: `{}`, want: "Tool call was denied: denied by user", wantDecidedBy: "user"},
+		{name: "ask edited", mode: "ask", approver: edit, arguments: `{"subcommand":"push"}`, want: `ran {"subcommand":"status"}`, wantDecidedBy: "user"},
+		{name: "ask without terminal", mode: "ask", arguments: `{}`, want: "Tool call was denied: approval required but no interactive terminal is available", wantDecidedBy: "non-interactive"},
+		{name: "pattern allow", mode: "pattern", arguments: `{"subcommand":"log"}`, want: `ran {"subcommand":"log"}`, wantDecidedBy: "policy"},
+		{name: "pattern deny wins over allow", mode: "pattern", approver: approve, arguments: `{"subcommand":"log","force":true}`, want: "Tool call was denied: arguments match deny rule", wantDecidedBy: "policy"},
+		{name: "pattern deny in value", mode: "pattern", approver: approve, arguments: `{"subcommand":"status; git push"}`, want: "Tool call was denied: arguments match deny rule", wantDecidedBy: "policy"},
+		{name: "pattern no match asks", mode: "pattern", approver: approve, arguments: `{"subcommand":"diff"}`, want: `ran {"subcommand":"diff"}`, wantDecidedBy: "user"},
+		{name: "pattern no match without terminal", mode: "pattern", arguments: `{"subcommand":"diff"}`, want: "Tool call was denied: approval required", wantDecidedBy: "non-interactive"},
+		// The rules see compact JSON with sorted keys and unescaped strings
+		{name: "pattern spaces", mode: "pattern", arguments: `{ "subcommand" : "log" }`, want: "ran ", wantDecidedBy: "policy"},
+		{name: "pattern unicode escape", mode: "pattern", arguments: `{"subcommand":"\u006cog"}`, want: "ran ", wantDecidedBy: "policy"},
+		{name: "pattern escaped slash", mode: "pattern", arguments: `{"path":"\/tmp\/a"}`, want: "ran ", wantDecidedBy: "policy"},
+		{name: "pattern escaped deny", mode: "pattern", approver: approve, arguments: `{"subcommand":"pu\u0073h"}`, want: "Tool call was denied: arguments match deny rule", wantDecidedBy: "policy"},
+		{name: "pattern key order", mode: "pattern", approver: approve, arguments: `{"subcommand":"log",  "force" : true}`, want: "Tool call was denied: arguments match deny rule", wantDecidedBy: "policy"},
+		{name: "pattern invalid JSON", mode: "pattern", arguments: `"subcommand":"log"`, want: "ran ", wantDecidedBy: "policy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			SetApprover(tt.approver)
+			defer SetApprover(nil)
+
+			gated, err := WithApproval("git", tt.mode, rules, echoTool{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := gated.InvokableRun(context.Background(), tt.arguments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(result, tt.want) {
+				t.Errorf("expected result %q, got %q", tt.want, result)
+			}
+
+			entries := readAudit(t)
+			if tt.wantDecidedBy == "" {
+				if len(entries) != 0 {
+					t.Errorf("expected no audit entry, got %+v", entries)
+				}
+				return
+			}
+			if len(entries) != 1 {
+				t.Fatalf("expected one audit entry, got %+v", entries)
+			}
+			entry := entries[0]
+			if entry.Tool != "git" || entry.Arguments != tt.arguments || entry.DecidedBy != tt.wantDecidedBy {
+				t.Errorf("expected git %s decided by %s, got %+v", tt.arguments, tt.wantDecidedBy, entry)
+			}
+			if entry.Approved != strings.HasPrefix(result, "ran ") {
+				t.Errorf("expected the audit entry to match the result %q, got %+v", result, entry)
+			}
+		})
+	}
+}
+
+func TestWithApprovalStreamable(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	gated, err := WithApproval("stream", "never", nil, &streamEchoTool{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, ok := gated.(tool.StreamableTool)
+	if !ok {
+		t.Fatal("expected the wrapped tool to keep streaming")
+	}
+	sr, err := st.StreamableRun(context.Background(), `{}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sr.Close()
+	chunk, err := sr.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(chunk, "Tool call was denied") {
+		t.Errorf("expected the denial, got %q", chunk)
+	}
+}
+
+// streamEchoTool is an echoTool that also streams
+type streamEchoTool struct {
+	echoTool
+}
+
+func (streamEchoTool) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (*schema.StreamReader[string], error) {
+	return schema.StreamReaderFromArray([]string{"ran ", argumentsInJSON}), nil
+}
+
+func TestValidateApproval(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		rules   *config.ApprovalRules
+		wantErr string
+	}{
+		{name: "default"},
+		{name: "ask", mode: "ask"},
+		{name: "pattern", mode: "pattern", rules: &config.ApprovalRules{Deny: []string{"rm"}}},
+		{name: "pattern without rules", mode: "pattern", rules: &config.ApprovalRules{}, wantErr: "requires approval_rules"},
+		{name: "invalid rule", mode: "ask", rules: &config.ApprovalRules{Allow: []string{"("}}, wantErr: "invalid approval rule"},
+		{name: "unknown mode", mode: "sometimes", wantErr: "unsupported approval"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateApproval(tt.mode, tt.rules)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
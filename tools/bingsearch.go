@@ -8,6 +8,10 @@ import (
 	"github.com/tk103331/eino-cli/config"
 )
 
+func init() {
+	Register("bingsearch", NewBingSearchTool, "api_key")
+}
+
 // NewBingSearchTool creates Bing search tool
 func NewBingSearchTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
 	ctx := context.Background()
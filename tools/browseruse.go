@@ -8,6 +8,10 @@ import (
 	"github.com/tk103331/eino-cli/config"
 )
 
+func init() {
+	Register("browseruse", NewBrowserUseTool)
+}
+
 // NewBrowserUseTool creates browser usage tool
 func NewBrowserUseTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
 	ctx := context.Background()
@@ -8,6 +8,10 @@ import (
 	"github.com/tk103331/eino-cli/config"
 )
 
+func init() {
+	Register("commandline", NewCommandLineTool)
+}
+
 // NewCommandLineTool creates command line editor tool
 func NewCommandLineTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
 	ctx := context.Background()
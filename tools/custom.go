@@ -0,0 +1,10 @@
+package tools
+
+import "github.com/tk103331/eino-cli/tools/custom"
+
+// Custom tools live in their own package, which cannot import the registry
+func init() {
+	Register("customhttp", custom.NewHTTPTool, "url")
+	Register("customexec", custom.NewExecTool, "cmd")
+	RegisterBundle("openapi", custom.NewOpenAPITools, "spec")
+}
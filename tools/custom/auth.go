@@ -0,0 +1,103 @@
+package custom
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// Authentication schemes of HTTP tools
+const (
+	AuthBearer = "bearer"
+	AuthBasic  = "basic"
+	AuthAPIKey = "api_key"
+	AuthOAuth2 = "oauth2"
+)
+
+// AuthConfig authenticates the requests of an HTTP tool, secrets are usually given as ${ENV_VAR} references
+type AuthConfig struct {
+	Type         string            `yaml:"type"`          // bearer, basic, api_key or oauth2
+	Token        string            `yaml:"token"`         // Bearer token
+	Username     string            `yaml:"username"`      // Basic auth user name
+	Password     string            `yaml:"password"`      // Basic auth password
+	Header       string            `yaml:"header"`        // API key header, default X-API-Key
+	Key          string            `yaml:"key"`           // API key
+	TokenURL     string            `yaml:"token_url"`     // OAuth2 token endpoint
+	ClientID     string            `yaml:"client_id"`     // OAuth2 client ID
+	ClientSecret string            `yaml:"client_secret"` // OAuth2 client secret
+	Scopes       []string          `yaml:"scopes"`        // OAuth2 scopes
+	Params       map[string]string `yaml:"params"`        // Additional OAuth2 token request parameters, such as audience
+}
+
+// authFunc adds the credentials to a request
+type authFunc func(req *http.Request) error
+
+// newAuth checks the auth settings and returns the function that authenticates requests. OAuth2 tokens
+// are fetched with client, cached and renewed when they expire.
+func newAuth(cfg *AuthConfig, client *http.Client) (authFunc, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	switch strings.ToLower(cfg.Type) {
+	case AuthBearer:
+		if cfg.Token == "" {
+			return nil, fmt.Errorf("bearer auth requires token")
+		}
+		return func(req *http.Request) error {
+			req.Header.Set("Authorization", "Bearer "+cfg.Token)
+			return nil
+		}, nil
+	case AuthBasic:
+		if cfg.Username == "" {
+			return nil, fmt.Errorf("basic auth requires username")
+		}
+		return func(req *http.Request) error {
+			req.SetBasicAuth(cfg.Username, cfg.Password)
+			return nil
+		}, nil
+	case AuthAPIKey:
+		if cfg.Key == "" {
+			return nil, fmt.Errorf("api_key auth requires key")
+		}
+		header := cfg.Header
+		if header == "" {
+			header = "X-API-Key"
+		}
+		return func(req *http.Request) error {
+			req.Header.Set(header, cfg.Key)
+			return nil
+		}, nil
+	case AuthOAuth2:
+		if cfg.TokenURL == "" || cfg.ClientID == "" {
+			return nil, fmt.Errorf("oauth2 auth requires token_url and client_id")
+		}
+		credentials := &clientcredentials.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			TokenURL:     cfg.TokenURL,
+			Scopes:       cfg.Scopes,
+		}
+		if len(cfg.Params) > 0 {
+			credentials.EndpointParams = make(map[string][]string, len(cfg.Params))
+			for key, value := range cfg.Params {
+				credentials.EndpointParams[key] = []string{value}
+			}
+		}
+		// The token source outlives single calls, so it gets its own context
+		tokens := credentials.TokenSource(context.WithValue(context.Background(), oauth2.HTTPClient, client))
+		return func(req *http.Request) error {
+			token, err := tokens.Token()
+			if err != nil {
+				return fmt.Errorf("failed to get OAuth2 token: %v", err)
+			}
+			token.SetAuthHeader(req)
+			return nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported auth type %q (must be bearer, basic, api_key or oauth2)", cfg.Type)
+	}
+}
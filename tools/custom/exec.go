@@ -6,22 +6,44 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/schema"
 	"github.com/tk103331/eino-cli/config"
 )
 
+// defaultMaxOutput is the number of bytes kept of stdout and of stderr
+const defaultMaxOutput = 1 << 20
+
 // ExecConfig execution tool configuration structure
 type ExecConfig struct {
-	Cmd     string            `yaml:"cmd"`     // Execution command template
-	WorkDir string            `yaml:"workdir"` // Working directory
-	Env     map[string]string `yaml:"env"`     // Environment variables
-	Timeout int               `yaml:"timeout"` // Timeout in seconds
+	Cmd           string            `yaml:"cmd"`            // Execution command template, split into words before rendering
+	Args          []string          `yaml:"args"`           // Argument templates appended to cmd, each rendered into one argument
+	Shell         bool              `yaml:"shell"`          // Run the rendered cmd with sh -c
+	WorkDir       string            `yaml:"workdir"`        // Working directory
+	Env           map[string]string `yaml:"env"`            // Environment variables
+	Timeout       int               `yaml:"timeout"`        // Timeout in seconds
+	AllowCommands []string          `yaml:"allow_commands"` // Programs that may run, any if empty
+	DenyCommands  []string          `yaml:"deny_commands"`  // Programs that must not run
+	MaxOutput     int               `yaml:"max_output"`     // Bytes kept of stdout and of stderr, half from the start and half from the end
+	Sandbox       *SandboxConfig    `yaml:"sandbox"`        // Restricted mode, Linux only
+}
+
+// SandboxConfig restricts the command in Linux namespaces and with resource limits
+type SandboxConfig struct {
+	ReadOnly     []string `yaml:"read_only" json:"read_only"`         // Directories mounted read-only
+	Network      bool     `yaml:"network" json:"network"`             // Keep network access, by default there is none
+	MaxMemory    int      `yaml:"max_memory" json:"max_memory"`       // Address space limit in MB
+	MaxCPUTime   int      `yaml:"max_cpu_time" json:"max_cpu_time"`   // CPU time limit in seconds
+	MaxProcesses int      `yaml:"max_processes" json:"max_processes"` // Process limit of the user
+	MaxFileSize  int      `yaml:"max_file_size" json:"max_file_size"` // Size limit of written files in MB
 }
 
 // ExecTool execution tool implementation
@@ -39,6 +61,29 @@ func NewExecTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
 		if cmdValue, exists := cfg.Config["cmd"]; exists {
 			execConfig.Cmd = cmdValue.String()
 		}
+		for key, target := range map[string]*[]string{
+			"args":           &execConfig.Args,
+			"allow_commands": &execConfig.AllowCommands,
+			"deny_commands":  &execConfig.DenyCommands,
+		} {
+			if value, exists := cfg.Config[key]; exists {
+				for _, item := range value.Array() {
+					*target = append(*target, item.String())
+				}
+			}
+		}
+		if shellValue, exists := cfg.Config["shell"]; exists {
+			execConfig.Shell = shellValue.Bool()
+		}
+		if maxOutputValue, exists := cfg.Config["max_output"]; exists {
+			execConfig.MaxOutput = maxOutputValue.Int()
+		}
+		if sandboxValue, exists := cfg.Config["sandbox"]; exists && sandboxValue.IsMap() {
+			execConfig.Sandbox = &SandboxConfig{}
+			if err := sandboxValue.Parse(execConfig.Sandbox); err != nil {
+				return nil, fmt.Errorf("invalid exec tool sandbox: %v", err)
+			}
+		}
 		if workdirValue, exists := cfg.Config["workdir"]; exists {
 			execConfig.WorkDir = workdirValue.String()
 		}
@@ -58,10 +103,29 @@ func NewExecTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
 		return nil, fmt.Errorf("exec tool must configure cmd attribute")
 	}
 
+	if execConfig.Shell && len(execConfig.Args) > 0 {
+		return nil, fmt.Errorf("exec tool cannot combine shell with args")
+	}
+	if execConfig.Shell && (len(execConfig.AllowCommands) > 0 || len(execConfig.DenyCommands) > 0) {
+		// A shell script can run any program, the lists could only be checked against its first command
+		return nil, fmt.Errorf("exec tool cannot combine shell with allow_commands or deny_commands")
+	}
+	if _, err := splitWords(execConfig.Cmd); err != nil {
+		return nil, fmt.Errorf("invalid exec tool cmd: %v", err)
+	}
+	if execConfig.Sandbox != nil {
+		if err := validateSandbox(execConfig.Sandbox); err != nil {
+			return nil, err
+		}
+	}
+
 	// Set default values
 	if execConfig.Timeout == 0 {
 		execConfig.Timeout = 30 // Default 30 seconds timeout
 	}
+	if execConfig.MaxOutput == 0 {
+		execConfig.MaxOutput = defaultMaxOutput
+	}
 
 	// Get description information
 	desc := cfg.Description
@@ -118,28 +182,106 @@ func (e *ExecTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
 
 // InvokableRun implements InvokableTool interface
 func (e *ExecTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(e.execConfig.Timeout)*time.Second)
+	defer cancel()
+	cmd, err := e.command(ctx, argumentsInJSON)
+	if err != nil {
+		return "", err
+	}
+
+	// Execute command and capture output, keeping the head and tail of long output
+	stdout := &outputWindow{max: e.execConfig.MaxOutput}
+	stderr := &outputWindow{max: e.execConfig.MaxOutput}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err = cmd.Run()
+	if err != nil {
+		// If command execution fails, return error message and stderr
+		errorMsg := fmt.Sprintf("command execution failed: %v", err)
+		if stderr.Len() > 0 {
+			errorMsg += fmt.Sprintf("\nstderr: %s", stderr.String())
+		}
+		if stdout.Len() > 0 {
+			errorMsg += fmt.Sprintf("\nstdout: %s", stdout.String())
+		}
+		return "", errors.New(errorMsg)
+	}
+
+	// Return standard output
+	result := stdout.String()
+	if stderr.Len() > 0 {
+		// If there's stderr but command succeeded, append stderr as warning
+		result += fmt.Sprintf("\n[warning] %s", stderr.String())
+	}
+
+	return result, nil
+}
+
+// StreamableRun implements StreamableTool interface. The combined stdout and stderr are reported line by line
+// as progress while the command runs; the stream carries the head and tail of the output.
+func (e *ExecTool) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (*schema.StreamReader[string], error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(e.execConfig.Timeout)*time.Second)
+	cmd, err := e.command(ctx, argumentsInJSON)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("command execution failed: %v", err)
+	}
+	// The reader sees the exit error once the output is read
+	go func() {
+		pw.CloseWithError(cmd.Wait())
+	}()
+
+	sr, sw := schema.Pipe[string](16)
+	go func() {
+		defer cancel()
+		defer sw.Close()
+		// Unblocks the command if the stream is abandoned
+		defer pr.Close()
+		if err := streamLines(ctx, pr, &outputWindow{max: e.execConfig.MaxOutput}, sw); err != nil {
+			sw.Send("", fmt.Errorf("command execution failed: %v", err))
+		}
+	}()
+	return sr, nil
+}
+
+// command parses the arguments and creates the command with its working directory and environment,
+// restricted when the sandbox is configured
+func (e *ExecTool) command(ctx context.Context, argumentsInJSON string) (*exec.Cmd, error) {
 	// Parse parameters
 	var args map[string]interface{}
 	if argumentsInJSON != "" {
 		if err := json.Unmarshal([]byte(argumentsInJSON), &args); err != nil {
-			return "", fmt.Errorf("failed to parse parameters: %v", err)
+			return nil, fmt.Errorf("failed to parse parameters: %v", err)
 		}
 	}
 
-	// Render command template
-	cmdStr, err := e.renderTemplate(e.execConfig.Cmd, args)
+	// Render command line
+	argv, err := e.commandLine(args)
 	if err != nil {
-		return "", fmt.Errorf("failed to render command template: %v", err)
+		return nil, err
 	}
-
-	// Parse command and arguments
-	cmdParts := strings.Fields(cmdStr)
-	if len(cmdParts) == 0 {
-		return "", fmt.Errorf("command is empty")
+	if err := e.checkCommand(argv); err != nil {
+		return nil, err
 	}
 
 	// Create command
-	cmd := exec.CommandContext(ctx, cmdParts[0], cmdParts[1:]...)
+	var cmd *exec.Cmd
+	if e.execConfig.Sandbox != nil {
+		if cmd, err = sandboxCommand(ctx, e.execConfig.Sandbox, argv); err != nil {
+			return nil, err
+		}
+	} else {
+		cmd = exec.CommandContext(ctx, argv[0], argv[1:]...)
+	}
 
 	// Set working directory
 	if e.execConfig.WorkDir != "" {
@@ -148,7 +290,7 @@ func (e *ExecTool) InvokableRun(ctx context.Context, argumentsInJSON string, opt
 		if strings.HasPrefix(workDir, "~/") {
 			homeDir, err := os.UserHomeDir()
 			if err != nil {
-				return "", fmt.Errorf("failed to get user home directory: %v", err)
+				return nil, fmt.Errorf("failed to get user home directory: %v", err)
 			}
 			workDir = strings.Replace(workDir, "~", homeDir, 1)
 		}
@@ -156,54 +298,93 @@ func (e *ExecTool) InvokableRun(ctx context.Context, argumentsInJSON string, opt
 	}
 
 	// Set environment variables
-	cmd.Env = os.Environ()
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
 	if e.execConfig.Env != nil {
 		for key, value := range e.execConfig.Env {
 			envValue, err := e.renderTemplate(value, args)
 			if err != nil {
-				return "", fmt.Errorf("failed to render environment variable template: %v", err)
+				return nil, fmt.Errorf("failed to render environment variable template: %v", err)
 			}
 			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, envValue))
 		}
 	}
+	return cmd, nil
+}
 
-	// Execute command and capture output
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+// commandLine renders the program and its arguments. Words of cmd and elements of args are rendered one by one,
+// so a parameter value never turns into several arguments; words of cmd that render empty are dropped.
+// In shell mode the whole cmd is rendered and passed to sh -c.
+func (e *ExecTool) commandLine(args map[string]interface{}) ([]string, error) {
+	if e.execConfig.Shell {
+		script, err := e.renderTemplate(e.execConfig.Cmd, args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render command template: %v", err)
+		}
+		return []string{"sh", "-c", script}, nil
+	}
 
-	err = cmd.Run()
+	words, err := splitWords(e.execConfig.Cmd)
 	if err != nil {
-		// If command execution fails, return error message and stderr
-		errorMsg := fmt.Sprintf("command execution failed: %v", err)
-		if stderr.Len() > 0 {
-			errorMsg += fmt.Sprintf("\nstderr: %s", stderr.String())
+		return nil, fmt.Errorf("invalid command template: %v", err)
+	}
+	var argv []string
+	for _, word := range words {
+		rendered, err := e.renderTemplate(word, args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render command template: %v", err)
 		}
-		if stdout.Len() > 0 {
-			errorMsg += fmt.Sprintf("\nstdout: %s", stdout.String())
+		if rendered != "" {
+			argv = append(argv, rendered)
 		}
-		return "", errors.New(errorMsg)
 	}
-
-	// Return standard output
-	result := stdout.String()
-	if stderr.Len() > 0 {
-		// If there's stderr but command succeeded, append stderr as warning
-		result += fmt.Sprintf("\n[warning] %s", stderr.String())
+	if len(argv) == 0 {
+		return nil, fmt.Errorf("command is empty")
 	}
+	for _, arg := range e.execConfig.Args {
+		rendered, err := e.renderTemplate(arg, args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render argument template: %v", err)
+		}
+		argv = append(argv, rendered)
+	}
+	return argv, nil
+}
 
-	return result, nil
+// checkCommand applies the allow and deny lists to the program, they are never set in shell mode
+func (e *ExecTool) checkCommand(argv []string) error {
+	if len(e.execConfig.AllowCommands) == 0 && len(e.execConfig.DenyCommands) == 0 {
+		return nil
+	}
+	program := argv[0]
+	matches := func(list []string) bool {
+		for _, name := range list {
+			if name == program || name == filepath.Base(program) {
+				return true
+			}
+		}
+		return false
+	}
+	if matches(e.execConfig.DenyCommands) {
+		return fmt.Errorf("command %s is denied", program)
+	}
+	if len(e.execConfig.AllowCommands) > 0 && !matches(e.execConfig.AllowCommands) {
+		return fmt.Errorf("command %s is not allowed", program)
+	}
+	return nil
 }
 
-// renderTemplate renders template
+// renderTemplate renders template, values can be shell-quoted with {{quote .name}}; declared params that were
+// not given render empty
 func (e *ExecTool) renderTemplate(templateStr string, args map[string]interface{}) (string, error) {
-	tmpl, err := template.New("exec").Parse(templateStr)
+	tmpl, err := template.New("exec").Funcs(template.FuncMap{"quote": shellQuote}).Option("missingkey=error").Parse(templateStr)
 	if err != nil {
 		return "", err
 	}
 
 	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, args); err != nil {
+	if err := tmpl.Execute(&buf, templateArgs(e.config.Params, args)); err != nil {
 		return "", err
 	}
 
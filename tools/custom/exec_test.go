@@ -0,0 +1,42 @@
+package custom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tk103331/eino-cli/config"
+	"gopkg.in/yaml.v3"
+)
+
+func TestNewExecToolConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string // Expected substring of the error, empty if the tool is valid
+	}{
+		{name: "program", config: "cmd: grep {{.pattern}}\nallow_commands: [grep]"},
+		{name: "shell", config: "cmd: uname -a && df -h\nshell: true"},
+		{name: "shell with args", config: "cmd: echo\nshell: true\nargs: [a]", wantErr: "cannot combine shell with args"},
+		{name: "shell with allow_commands", config: "cmd: ls; rm -rf /tmp/x\nshell: true\nallow_commands: [ls]", wantErr: "cannot combine shell with allow_commands"},
+		{name: "shell with deny_commands", config: "cmd: ls\nshell: true\ndeny_commands: [rm]", wantErr: "cannot combine shell with allow_commands or deny_commands"},
+		{name: "unterminated quote", config: "cmd: echo 'a", wantErr: "unterminated single quote"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg config.Tool
+			if err := yaml.Unmarshal([]byte("type: customexec\nconfig:\n  "+strings.ReplaceAll(tt.config, "\n", "\n  ")), &cfg); err != nil {
+				t.Fatal(err)
+			}
+			_, err := NewExecTool("test", cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
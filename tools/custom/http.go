@@ -4,24 +4,41 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/schema"
+	"github.com/tidwall/gjson"
 	"github.com/tk103331/eino-cli/config"
+	"github.com/tk103331/eino-cli/logger"
 )
 
+// defaultMaxResponse is the number of bytes read from a response
+const defaultMaxResponse = 10 << 20
+
 // HTTPConfig HTTP tool configuration structure
 type HTTPConfig struct {
-	URL     string            `yaml:"url"`     // Request URL template
-	Method  string            `yaml:"method"`  // HTTP method
-	Headers map[string]string `yaml:"headers"` // Request headers
-	Body    string            `yaml:"body"`    // Request body template
-	Timeout int               `yaml:"timeout"` // Timeout in seconds
+	URL                string            `yaml:"url"`                  // Request URL template
+	Method             string            `yaml:"method"`               // HTTP method
+	Headers            map[string]string `yaml:"headers"`              // Request headers
+	Query              map[string]string `yaml:"query"`                // Query parameter templates, parameters that render empty are left out
+	Body               string            `yaml:"body"`                 // Request body template
+	JSONBody           []string          `yaml:"json_body"`            // Params sent as a JSON object body, typed by their declared type
+	Auth               *AuthConfig       `yaml:"auth"`                 // Request authentication
+	Extract            string            `yaml:"extract"`              // gjson path selecting the part of a JSON response returned to the model
+	MaxResponse        int               `yaml:"max_response"`         // Bytes read from a response, larger responses fail
+	MaxOutput          int               `yaml:"max_output"`           // Bytes returned to the model, half from the start and half from the end
+	Retries            int               `yaml:"retries"`              // Retries of idempotent requests answered with a 5xx status
+	RetryNonIdempotent bool              `yaml:"retry_non_idempotent"` // Also retry POST and PATCH requests, which the server may have applied
+	Timeout            int               `yaml:"timeout"`              // Timeout in seconds
 }
 
 // HTTPTool HTTP tool implementation
@@ -29,6 +46,8 @@ type HTTPTool struct {
 	info       *schema.ToolInfo
 	config     config.Tool
 	httpConfig *HTTPConfig
+	client     *http.Client
+	auth       authFunc
 }
 
 // NewHTTPTool creates HTTP tool
@@ -45,13 +64,44 @@ func NewHTTPTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
 		if bodyValue, exists := cfg.Config["body"]; exists {
 			httpConfig.Body = bodyValue.String()
 		}
+		if jsonBodyValue, exists := cfg.Config["json_body"]; exists {
+			for _, item := range jsonBodyValue.Array() {
+				httpConfig.JSONBody = append(httpConfig.JSONBody, item.String())
+			}
+		}
+		if extractValue, exists := cfg.Config["extract"]; exists {
+			httpConfig.Extract = extractValue.String()
+		}
+		if maxResponseValue, exists := cfg.Config["max_response"]; exists {
+			httpConfig.MaxResponse = maxResponseValue.Int()
+		}
+		if maxOutputValue, exists := cfg.Config["max_output"]; exists {
+			httpConfig.MaxOutput = maxOutputValue.Int()
+		}
+		if retriesValue, exists := cfg.Config["retries"]; exists {
+			httpConfig.Retries = retriesValue.Int()
+		}
+		if retryValue, exists := cfg.Config["retry_non_idempotent"]; exists {
+			httpConfig.RetryNonIdempotent = retryValue.Bool()
+		}
 		if timeoutValue, exists := cfg.Config["timeout"]; exists {
 			httpConfig.Timeout = timeoutValue.Int()
 		}
-		if headersValue, exists := cfg.Config["headers"]; exists && headersValue.IsMap() {
-			httpConfig.Headers = make(map[string]string)
-			for k, v := range headersValue.Map() {
-				httpConfig.Headers[k] = v.String()
+		for key, target := range map[string]*map[string]string{
+			"headers": &httpConfig.Headers,
+			"query":   &httpConfig.Query,
+		} {
+			if value, exists := cfg.Config[key]; exists && value.IsMap() {
+				*target = make(map[string]string)
+				for k, v := range value.Map() {
+					(*target)[k] = v.String()
+				}
+			}
+		}
+		if authValue, exists := cfg.Config["auth"]; exists && authValue.IsMap() {
+			httpConfig.Auth = &AuthConfig{}
+			if err := authValue.Parse(httpConfig.Auth); err != nil {
+				return nil, fmt.Errorf("invalid http tool auth: %v", err)
 			}
 		}
 	}
@@ -68,6 +118,30 @@ func NewHTTPTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
 	if httpConfig.Timeout == 0 {
 		httpConfig.Timeout = 30 // Default 30 seconds timeout
 	}
+	if httpConfig.MaxResponse == 0 {
+		httpConfig.MaxResponse = defaultMaxResponse
+	}
+	if httpConfig.MaxOutput == 0 {
+		httpConfig.MaxOutput = defaultMaxOutput
+	}
+
+	if httpConfig.Body != "" && len(httpConfig.JSONBody) > 0 {
+		return nil, fmt.Errorf("http tool cannot combine body with json_body")
+	}
+	for _, name := range httpConfig.JSONBody {
+		if _, ok := findParam(cfg.Params, name); !ok {
+			return nil, fmt.Errorf("http tool json_body param %q is not declared in params", name)
+		}
+	}
+	if httpConfig.Retries < 0 {
+		return nil, fmt.Errorf("http tool retries must not be negative")
+	}
+
+	client := &http.Client{Timeout: time.Duration(httpConfig.Timeout) * time.Second}
+	auth, err := newAuth(httpConfig.Auth, client)
+	if err != nil {
+		return nil, fmt.Errorf("invalid http tool auth: %v", err)
+	}
 
 	// Get description information
 	desc := cfg.Description
@@ -114,6 +188,8 @@ func NewHTTPTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
 		info:       toolInfo,
 		config:     cfg,
 		httpConfig: httpConfig,
+		client:     client,
+		auth:       auth,
 	}, nil
 }
 
@@ -122,36 +198,142 @@ func (h *HTTPTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
 	return h.info, nil
 }
 
-// InvokableRun implements InvokableTool interface
+// InvokableRun implements InvokableTool interface. Responses with a non-2xx status are returned as a
+// structured error result, so that the model can react to them.
 func (h *HTTPTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	resp, err := h.do(ctx, argumentsInJSON)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	return h.result(resp)
+}
+
+// StreamableRun implements StreamableTool interface. The response body is reported line by line as progress
+// while it arrives, which suits streamed and long responses; the stream carries its head and tail.
+// Error responses and extracted results are sent at once.
+func (h *HTTPTool) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (*schema.StreamReader[string], error) {
+	resp, err := h.do(ctx, argumentsInJSON)
+	if err != nil {
+		return nil, err
+	}
+	if !successStatus(resp.StatusCode) || h.httpConfig.Extract != "" {
+		defer resp.Body.Close()
+		result, err := h.result(resp)
+		if err != nil {
+			return nil, err
+		}
+		return schema.StreamReaderFromArray([]string{result}), nil
+	}
+
+	sr, sw := schema.Pipe[string](16)
+	go func() {
+		defer sw.Close()
+		defer resp.Body.Close()
+		body := &responseReader{r: resp.Body, remaining: h.httpConfig.MaxResponse}
+		if err := streamLines(ctx, body, &outputWindow{max: h.httpConfig.MaxOutput}, sw); err != nil {
+			sw.Send("", fmt.Errorf("failed to read response: %v", err))
+		}
+	}()
+	return sr, nil
+}
+
+// do renders and sends the request, retrying it while the server answers with a 5xx status,
+// unless the method is not idempotent and retry_non_idempotent is not set
+func (h *HTTPTool) do(ctx context.Context, argumentsInJSON string) (*http.Response, error) {
 	// Parse parameters
 	var args map[string]interface{}
 	if argumentsInJSON != "" {
 		if err := json.Unmarshal([]byte(argumentsInJSON), &args); err != nil {
-			return "", fmt.Errorf("failed to parse parameters: %v", err)
+			return nil, fmt.Errorf("failed to parse parameters: %v", err)
+		}
+	}
+
+	// Prepare request body, rendered once and sent again on retries
+	var body []byte
+	if h.httpConfig.Body != "" {
+		bodyStr, err := h.renderTemplate(h.httpConfig.Body, args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render request body template: %v", err)
 		}
+		body = []byte(bodyStr)
+	} else if len(h.httpConfig.JSONBody) > 0 {
+		var err error
+		if body, err = h.jsonBody(args); err != nil {
+			return nil, err
+		}
+	}
+
+	retries := h.httpConfig.Retries
+	if !h.httpConfig.RetryNonIdempotent && !idempotentMethod(h.httpConfig.Method) {
+		// A failed POST may still have been applied, repeating it could create a second ticket or payment
+		retries = 0
 	}
+	for attempt := 0; ; attempt++ {
+		req, err := h.newRequest(ctx, args, body)
+		if err != nil {
+			return nil, err
+		}
 
+		// Send HTTP request
+		resp, err := h.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send HTTP request: %v", err)
+		}
+		if resp.StatusCode < 500 || attempt >= retries {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		delay := retryDelay(attempt)
+		logger.Warn("TOOL", fmt.Sprintf("HTTP tool %s got status %d, retrying in %v", h.info.Name, resp.StatusCode, delay))
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// newRequest creates the request with its URL, query, headers and authentication
+func (h *HTTPTool) newRequest(ctx context.Context, args map[string]interface{}, body []byte) (*http.Request, error) {
 	// Template replacement for URL
-	url, err := h.renderTemplate(h.httpConfig.URL, args)
+	rawURL, err := h.renderTemplate(h.httpConfig.URL, args)
 	if err != nil {
-		return "", fmt.Errorf("failed to render URL template: %v", err)
+		return nil, fmt.Errorf("failed to render URL template: %v", err)
 	}
-
-	// Prepare request body
-	var body io.Reader
-	if h.httpConfig.Body != "" {
-		bodyStr, err := h.renderTemplate(h.httpConfig.Body, args)
+	if len(h.httpConfig.Query) > 0 {
+		u, err := url.Parse(rawURL)
 		if err != nil {
-			return "", fmt.Errorf("failed to render request body template: %v", err)
+			return nil, fmt.Errorf("invalid URL %q: %v", rawURL, err)
 		}
-		body = strings.NewReader(bodyStr)
+		query := u.Query()
+		for key, value := range h.httpConfig.Query {
+			queryValue, err := h.renderTemplate(value, args)
+			if err != nil {
+				return nil, fmt.Errorf("failed to render query parameter template: %v", err)
+			}
+			// Optional params that were not given render empty
+			if queryValue == "" {
+				continue
+			}
+			query.Set(key, queryValue)
+		}
+		u.RawQuery = query.Encode()
+		rawURL = u.String()
 	}
 
 	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, h.httpConfig.Method, url, body)
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, h.httpConfig.Method, rawURL, bodyReader)
 	if err != nil {
-		return "", fmt.Errorf("failed to create HTTP request: %v", err)
+		return nil, fmt.Errorf("failed to create HTTP request: %v", err)
+	}
+	if len(h.httpConfig.JSONBody) > 0 {
+		req.Header.Set("Content-Type", "application/json")
 	}
 
 	// Set request headers
@@ -159,45 +341,230 @@ func (h *HTTPTool) InvokableRun(ctx context.Context, argumentsInJSON string, opt
 		for key, value := range h.httpConfig.Headers {
 			headerValue, err := h.renderTemplate(value, args)
 			if err != nil {
-				return "", fmt.Errorf("failed to render request header template: %v", err)
+				return nil, fmt.Errorf("failed to render request header template: %v", err)
+			}
+			if headerValue == "" {
+				continue
 			}
 			req.Header.Set(key, headerValue)
 		}
 	}
 
-	// Send HTTP request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	if h.auth != nil {
+		if err := h.auth(req); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// jsonBody builds a JSON object from the json_body params, converting values to the declared param types.
+// Params that were not given are left out.
+func (h *HTTPTool) jsonBody(args map[string]interface{}) ([]byte, error) {
+	body := make(map[string]interface{}, len(h.httpConfig.JSONBody))
+	for _, name := range h.httpConfig.JSONBody {
+		value, exists := args[name]
+		if !exists || value == nil {
+			continue
+		}
+		param, _ := findParam(h.config.Params, name)
+		typed, err := typedValue(value, param.Type)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of param %s: %v", name, err)
+		}
+		body[name] = typed
+	}
+	data, err := json.Marshal(body)
 	if err != nil {
-		return "", fmt.Errorf("failed to send HTTP request: %v", err)
+		return nil, fmt.Errorf("failed to build JSON body: %v", err)
 	}
-	defer resp.Body.Close()
+	return data, nil
+}
 
-	// Read response
-	respBody, err := io.ReadAll(resp.Body)
+// result reads the response and turns it into the tool result: error statuses become a structured error,
+// successful responses are extracted and cut to max_output
+func (h *HTTPTool) result(resp *http.Response) (string, error) {
+	respBody, err := io.ReadAll(&responseReader{r: resp.Body, remaining: h.httpConfig.MaxResponse})
 	if err != nil {
 		return "", fmt.Errorf("failed to read response: %v", err)
 	}
 
-	// Check HTTP status code
-	if resp.StatusCode >= 400 {
-		return "", fmt.Errorf("HTTP request failed, status code: %d, response: %s", resp.StatusCode, string(respBody))
+	if !successStatus(resp.StatusCode) {
+		return h.statusError(resp, respBody)
 	}
 
-	return string(respBody), nil
+	if h.httpConfig.Extract != "" {
+		if !gjson.ValidBytes(respBody) {
+			return "", fmt.Errorf("cannot extract %q, response is not JSON", h.httpConfig.Extract)
+		}
+		value := gjson.GetBytes(respBody, h.httpConfig.Extract)
+		switch {
+		case !value.Exists():
+			respBody = []byte("null")
+		case value.Type == gjson.String:
+			respBody = []byte(value.Str)
+		default:
+			respBody = []byte(value.Raw)
+		}
+	}
+
+	window := &outputWindow{max: h.httpConfig.MaxOutput}
+	window.Write(respBody)
+	return window.String(), nil
 }
 
-// renderTemplate renders template
+// statusError describes a response with a non-2xx status as a JSON object, keeping a JSON body as is
+func (h *HTTPTool) statusError(resp *http.Response, respBody []byte) (string, error) {
+	window := &outputWindow{max: h.httpConfig.MaxOutput}
+	window.Write(respBody)
+	body := window.String()
+
+	details := map[string]interface{}{
+		"status":      resp.StatusCode,
+		"status_text": http.StatusText(resp.StatusCode),
+	}
+	if gjson.Valid(body) {
+		details["body"] = json.RawMessage(body)
+	} else if body != "" {
+		details["body"] = body
+	}
+	data, err := json.Marshal(map[string]interface{}{"error": details})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// templateFuncs are available in the templates of HTTP tools
+var templateFuncs = template.FuncMap{
+	"json":       templateJSON,
+	"pathEscape": templatePathEscape,
+	"join":       templateJoin,
+}
+
+// renderTemplate renders template, declared params that were not given render empty
 func (h *HTTPTool) renderTemplate(templateStr string, args map[string]interface{}) (string, error) {
-	tmpl, err := template.New("http").Parse(templateStr)
+	tmpl, err := template.New("http").Funcs(templateFuncs).Option("missingkey=error").Parse(templateStr)
 	if err != nil {
 		return "", err
 	}
 
 	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, args); err != nil {
+	if err := tmpl.Execute(&buf, templateArgs(h.config.Params, args)); err != nil {
 		return "", err
 	}
 
 	return buf.String(), nil
 }
+
+// successStatus reports whether a status code is 2xx
+func successStatus(code int) bool {
+	return code >= 200 && code < 300
+}
+
+// idempotentMethod reports whether repeating a request of the method has the same effect as sending it once
+func idempotentMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryDelay returns the wait before a retry, doubling from 500ms up to 8s
+func retryDelay(attempt int) time.Duration {
+	if attempt > 4 {
+		attempt = 4
+	}
+	return 500 * time.Millisecond << attempt
+}
+
+// findParam looks up a declared param by name
+func findParam(params []config.ToolParam, name string) (config.ToolParam, bool) {
+	for _, param := range params {
+		if param.Name == name {
+			return param, true
+		}
+	}
+	return config.ToolParam{}, false
+}
+
+// typedValue converts a param value to its declared type. Models sometimes send numbers and booleans
+// as strings, and arrays or objects as JSON text.
+func typedValue(value interface{}, paramType string) (interface{}, error) {
+	s, isString := value.(string)
+	if !isString {
+		return value, nil
+	}
+	switch paramType {
+	case "number":
+		return strconv.ParseFloat(s, 64)
+	case "integer":
+		return strconv.ParseInt(s, 10, 64)
+	case "boolean":
+		return strconv.ParseBool(s)
+	case "array", "object":
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(s), &parsed); err != nil {
+			return nil, err
+		}
+		return parsed, nil
+	}
+	return s, nil
+}
+
+// errResponseTooLarge is returned when a response exceeds max_response
+var errResponseTooLarge = errors.New("response exceeds max_response")
+
+// responseReader reads a response body and fails once more than remaining bytes are read
+type responseReader struct {
+	r         io.Reader
+	remaining int
+}
+
+// Read implements io.Reader
+func (r *responseReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, errResponseTooLarge
+	}
+	// Read one byte past the limit to tell a body of exactly remaining bytes from a larger one
+	if len(p) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.r.Read(p)
+	r.remaining -= n
+	if r.remaining < 0 {
+		return n + r.remaining, errResponseTooLarge
+	}
+	return n, err
+}
+
+// templateJSON encodes a value as JSON, available as the json template function
+func templateJSON(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+// templatePathEscape escapes a value for use in a URL path segment, available as the pathEscape template function
+func templatePathEscape(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return url.PathEscape(fmt.Sprint(value))
+}
+
+// templateJoin joins the elements of an array value, available as the join template function
+func templateJoin(value interface{}, sep string) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, sep)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,111 @@
+package custom
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tk103331/eino-cli/config"
+	"gopkg.in/yaml.v3"
+)
+
+// newTestHTTPTool creates an HTTP tool from the YAML of its config section
+func newTestHTTPTool(t *testing.T, toolConfig string) *HTTPTool {
+	t.Helper()
+	var cfg config.Tool
+	if err := yaml.Unmarshal([]byte("type: customhttp\nconfig:\n  "+strings.ReplaceAll(toolConfig, "\n", "\n  ")), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	httpTool, err := NewHTTPTool("test", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return httpTool.(*HTTPTool)
+}
+
+func TestHTTPToolRetries(t *testing.T) {
+	tests := []struct {
+		method       string
+		optIn        bool
+		wantRequests int32
+	}{
+		{method: "GET", wantRequests: 2},
+		{method: "put", wantRequests: 2},
+		{method: "DELETE", wantRequests: 2},
+		{method: "POST", wantRequests: 1},
+		{method: "PATCH", wantRequests: 1},
+		{method: "POST", optIn: true, wantRequests: 2},
+	}
+	for _, tt := range tests {
+		name := tt.method
+		if tt.optIn {
+			name += "/retry_non_idempotent"
+		}
+		t.Run(name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			h := newTestHTTPTool(t, fmt.Sprintf("url: %s\nmethod: %s\nretries: 1\nretry_non_idempotent: %t", server.URL, tt.method, tt.optIn))
+			resp, err := h.do(context.Background(), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusServiceUnavailable {
+				t.Errorf("expected the last 503 response, got %d", resp.StatusCode)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("expected %d requests, got %d", tt.wantRequests, got)
+			}
+		})
+	}
+}
+
+func TestHTTPToolOptionalParams(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments string
+		wantQuery string
+		wantTrace string
+		wantErr   string
+	}{
+		{name: "given", arguments: `{"q":"go","trace":"abc"}`, wantQuery: "q=go", wantTrace: "abc"},
+		{name: "left out", arguments: `{}`},
+		{name: "null", arguments: `{"q":null,"trace":null}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query string
+			var trace []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query, trace = r.URL.RawQuery, r.Header.Values("X-Trace")
+			}))
+			defer server.Close()
+
+			h := newTestHTTPTool(t, "url: "+server.URL+"/search\nquery:\n  q: \"{{.q}}\"\nheaders:\n  X-Trace: \"{{.trace}}\"")
+			h.config.Params = []config.ToolParam{{Name: "q", Type: "string"}, {Name: "trace", Type: "string"}}
+			if _, err := h.InvokableRun(context.Background(), tt.arguments); err != nil {
+				t.Fatal(err)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("expected query %q, got %q", tt.wantQuery, query)
+			}
+			if strings.Join(trace, ",") != tt.wantTrace {
+				t.Errorf("expected X-Trace %q, got %q", tt.wantTrace, trace)
+			}
+		})
+	}
+
+	h := newTestHTTPTool(t, "url: http://localhost/{{.missing}}")
+	if _, err := h.InvokableRun(context.Background(), `{}`); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected undeclared params to fail the URL template, got %v", err)
+	}
+}
@@ -0,0 +1,503 @@
+package custom
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/tk103331/eino-cli/config"
+	"github.com/tk103331/eino-cli/logger"
+)
+
+// maxSchemaDepth limits how deep request schemas are described to the model, specs may be recursive
+const maxSchemaDepth = 3
+
+// OpenAPIConfig configures a bundle of HTTP tools generated from the operations of an OpenAPI 3 spec
+type OpenAPIConfig struct {
+	Spec               string            `yaml:"spec"`                 // Spec file path or URL
+	BaseURL            string            `yaml:"base_url"`             // Server URL, defaults to the first server of the spec
+	Operations         []string          `yaml:"operations"`           // operationId or "METHOD /path" patterns of the imported operations, all if empty
+	Tags               []string          `yaml:"tags"`                 // Import only operations with one of these tags
+	Prefix             string            `yaml:"prefix"`               // Prepended to the tool names
+	Headers            map[string]string `yaml:"headers"`              // Headers sent with every request
+	Auth               *AuthConfig       `yaml:"auth"`                 // Authentication shared by all operations
+	MaxResponse        int               `yaml:"max_response"`         // Bytes read from a response
+	MaxOutput          int               `yaml:"max_output"`           // Bytes returned to the model
+	Retries            int               `yaml:"retries"`              // Retries of idempotent requests answered with a 5xx status
+	RetryNonIdempotent bool              `yaml:"retry_non_idempotent"` // Also retry POST and PATCH operations
+	Timeout            int               `yaml:"timeout"`              // Timeout in seconds
+}
+
+// NewOpenAPITools creates one HTTP tool per selected operation of an OpenAPI spec. The tools share
+// one HTTP client and the authentication, so OAuth2 tokens are fetched once for the bundle.
+func NewOpenAPITools(name string, cfg config.Tool) ([]tool.InvokableTool, error) {
+	// Initialize OpenAPIConfig
+	apiConfig := &OpenAPIConfig{}
+	if cfg.Config != nil {
+		for key, target := range map[string]*string{
+			"spec":     &apiConfig.Spec,
+			"base_url": &apiConfig.BaseURL,
+			"prefix":   &apiConfig.Prefix,
+		} {
+			if value, exists := cfg.Config[key]; exists {
+				*target = value.String()
+			}
+		}
+		for key, target := range map[string]*[]string{
+			"operations": &apiConfig.Operations,
+			"tags":       &apiConfig.Tags,
+		} {
+			if value, exists := cfg.Config[key]; exists {
+				for _, item := range value.Array() {
+					*target = append(*target, item.String())
+				}
+			}
+		}
+		for key, target := range map[string]*int{
+			"max_response": &apiConfig.MaxResponse,
+			"max_output":   &apiConfig.MaxOutput,
+			"retries":      &apiConfig.Retries,
+			"timeout":      &apiConfig.Timeout,
+		} {
+			if value, exists := cfg.Config[key]; exists {
+				*target = value.Int()
+			}
+		}
+		if retryValue, exists := cfg.Config["retry_non_idempotent"]; exists {
+			apiConfig.RetryNonIdempotent = retryValue.Bool()
+		}
+		if headersValue, exists := cfg.Config["headers"]; exists && headersValue.IsMap() {
+			apiConfig.Headers = make(map[string]string)
+			for k, v := range headersValue.Map() {
+				apiConfig.Headers[k] = v.String()
+			}
+		}
+		if authValue, exists := cfg.Config["auth"]; exists && authValue.IsMap() {
+			apiConfig.Auth = &AuthConfig{}
+			if err := authValue.Parse(apiConfig.Auth); err != nil {
+				return nil, fmt.Errorf("invalid openapi tool auth: %v", err)
+			}
+		}
+	}
+
+	// Check required attributes
+	if apiConfig.Spec == "" {
+		return nil, fmt.Errorf("openapi tool must configure spec attribute")
+	}
+	if apiConfig.Retries < 0 {
+		return nil, fmt.Errorf("openapi tool retries must not be negative")
+	}
+
+	// Set default values
+	if apiConfig.Timeout == 0 {
+		apiConfig.Timeout = 30 // Default 30 seconds timeout
+	}
+	if apiConfig.MaxResponse == 0 {
+		apiConfig.MaxResponse = defaultMaxResponse
+	}
+	if apiConfig.MaxOutput == 0 {
+		apiConfig.MaxOutput = defaultMaxOutput
+	}
+
+	client := &http.Client{Timeout: time.Duration(apiConfig.Timeout) * time.Second}
+	auth, err := newAuth(apiConfig.Auth, client)
+	if err != nil {
+		return nil, fmt.Errorf("invalid openapi tool auth: %v", err)
+	}
+
+	doc, specURL, err := loadSpec(apiConfig.Spec, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI spec %s: %v", apiConfig.Spec, err)
+	}
+	baseURL, err := serverURL(apiConfig.BaseURL, doc, specURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var tools []tool.InvokableTool
+	names := make(map[string]string)
+	for _, p := range sortedKeys(doc.Paths) {
+		item := doc.Paths[p]
+		operations := item.Operations()
+		for _, method := range sortedKeys(operations) {
+			op := operations[method]
+			method = strings.ToUpper(method)
+			if !apiConfig.selects(method, p, op) {
+				continue
+			}
+
+			t, err := newOperationTool(apiConfig, baseURL, method, p, item, op)
+			if err != nil {
+				logger.Warn("TOOL", fmt.Sprintf("OpenAPI tool %s skips %s %s: %v", name, method, p, err))
+				continue
+			}
+			t.client = client
+			t.auth = auth
+
+			if other, exists := names[t.info.Name]; exists {
+				return nil, fmt.Errorf("openapi tool %s: operations %s and %s %s both become tool %s", name, other, method, p, t.info.Name)
+			}
+			names[t.info.Name] = method + " " + p
+			tools = append(tools, t)
+		}
+	}
+	if len(tools) == 0 {
+		return nil, fmt.Errorf("openapi tool %s: no operation of %s matches the filter", name, apiConfig.Spec)
+	}
+	return tools, nil
+}
+
+// selects reports whether an operation passes the operation and tag filters
+func (c *OpenAPIConfig) selects(method, p string, op *openapi3.Operation) bool {
+	if len(c.Operations) > 0 {
+		matched := false
+		for _, pattern := range c.Operations {
+			if ok, _ := path.Match(pattern, op.OperationID); ok && op.OperationID != "" {
+				matched = true
+				break
+			}
+			if ok, _ := path.Match(pattern, method+" "+p); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(c.Tags) > 0 {
+		for _, tag := range op.Tags {
+			for _, wanted := range c.Tags {
+				if tag == wanted {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// newOperationTool describes an operation as an HTTP tool. Path, query and header parameters become
+// tool params; a JSON object body adds its properties as params, any other JSON body is one body param.
+func newOperationTool(c *OpenAPIConfig, baseURL, method, p string, item *openapi3.PathItem, op *openapi3.Operation) (*HTTPTool, error) {
+	httpConfig := &HTTPConfig{
+		Method:             method,
+		Headers:            make(map[string]string),
+		Query:              make(map[string]string),
+		MaxResponse:        c.MaxResponse,
+		MaxOutput:          c.MaxOutput,
+		Retries:            c.Retries,
+		RetryNonIdempotent: c.RetryNonIdempotent,
+		Timeout:            c.Timeout,
+	}
+	for key, value := range c.Headers {
+		httpConfig.Headers[key] = value
+	}
+
+	params := make(map[string]*schema.ParameterInfo)
+	var toolParams []config.ToolParam
+	urlTemplate := p
+
+	// Operation parameters override path item parameters of the same name and location
+	parameters := make(map[string]*openapi3.Parameter)
+	var order []string
+	for _, refs := range []openapi3.Parameters{item.Parameters, op.Parameters} {
+		for _, ref := range refs {
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			key := ref.Value.In + ":" + ref.Value.Name
+			if _, exists := parameters[key]; !exists {
+				order = append(order, key)
+			}
+			parameters[key] = ref.Value
+		}
+	}
+	for _, key := range order {
+		param := parameters[key]
+		info := schemaParam(param.Schema, 0)
+		if param.Description != "" {
+			info.Desc = param.Description
+		}
+		info.Required = param.Required || param.In == openapi3.ParameterInPath
+
+		value := fmt.Sprintf("{{index . %q}}", param.Name)
+		if info.Type == schema.Array {
+			value = fmt.Sprintf("{{join (index . %q) \",\"}}", param.Name)
+		}
+		switch param.In {
+		case openapi3.ParameterInPath:
+			urlTemplate = strings.ReplaceAll(urlTemplate, "{"+param.Name+"}", fmt.Sprintf("{{pathEscape (index . %q)}}", param.Name))
+		case openapi3.ParameterInQuery:
+			httpConfig.Query[param.Name] = value
+		case openapi3.ParameterInHeader:
+			httpConfig.Headers[param.Name] = value
+		default:
+			// Cookie parameters are not supported
+			if param.Required {
+				return nil, fmt.Errorf("unsupported required %s parameter %s", param.In, param.Name)
+			}
+			continue
+		}
+		params[param.Name] = info
+		toolParams = append(toolParams, config.ToolParam{Name: param.Name, Type: string(info.Type)})
+	}
+
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		body := op.RequestBody.Value
+		media := body.Content.Get("application/json")
+		if media == nil {
+			if body.Required {
+				return nil, fmt.Errorf("request body is not JSON")
+			}
+		} else {
+			bodyInfo := schemaParam(media.Schema, 0)
+			flatten := bodyInfo.Type == schema.Object && len(bodyInfo.SubParams) > 0
+			for name := range bodyInfo.SubParams {
+				if _, exists := params[name]; exists {
+					flatten = false
+				}
+			}
+			if flatten {
+				for _, name := range sortedKeys(bodyInfo.SubParams) {
+					info := bodyInfo.SubParams[name]
+					params[name] = info
+					httpConfig.JSONBody = append(httpConfig.JSONBody, name)
+					toolParams = append(toolParams, config.ToolParam{Name: name, Type: string(info.Type)})
+				}
+			} else {
+				if _, exists := params["body"]; exists {
+					return nil, fmt.Errorf("request body conflicts with parameter body")
+				}
+				if body.Description != "" {
+					bodyInfo.Desc = body.Description
+				}
+				bodyInfo.Required = body.Required
+				params["body"] = bodyInfo
+				toolParams = append(toolParams, config.ToolParam{Name: "body", Type: string(bodyInfo.Type)})
+				httpConfig.Body = `{{json (index . "body")}}`
+				httpConfig.Headers["Content-Type"] = "application/json"
+			}
+		}
+	}
+	httpConfig.URL = baseURL + urlTemplate
+
+	// Describe the operation
+	desc := strings.TrimSpace(op.Summary)
+	if op.Description != "" {
+		if desc != "" {
+			desc += "\n\n"
+		}
+		desc += strings.TrimSpace(op.Description)
+	}
+	if desc == "" {
+		desc = method + " " + p
+	}
+
+	name := op.OperationID
+	if name == "" {
+		name = strings.ToLower(method) + p
+	}
+	toolInfo := &schema.ToolInfo{
+		Name:        toolName(c.Prefix + name),
+		Desc:        desc,
+		ParamsOneOf: schema.NewParamsOneOfByParams(params),
+	}
+
+	return &HTTPTool{
+		info:       toolInfo,
+		config:     config.Tool{Type: "openapi", Description: desc, Params: toolParams},
+		httpConfig: httpConfig,
+	}, nil
+}
+
+// schemaParam describes a schema as a tool parameter
+func schemaParam(ref *openapi3.SchemaRef, depth int) *schema.ParameterInfo {
+	info := &schema.ParameterInfo{Type: schema.String}
+	if ref == nil || ref.Value == nil {
+		return info
+	}
+	s := ref.Value
+	if s.Type == "" {
+		switch {
+		case len(s.AllOf) > 0:
+			s = mergeAllOf(s)
+		case len(s.OneOf) > 0 && s.OneOf[0] != nil && s.OneOf[0].Value != nil:
+			s = s.OneOf[0].Value
+		case len(s.AnyOf) > 0 && s.AnyOf[0] != nil && s.AnyOf[0].Value != nil:
+			s = s.AnyOf[0].Value
+		}
+	}
+	info.Desc = s.Description
+
+	switch {
+	case s.Type == openapi3.TypeInteger:
+		info.Type = schema.Integer
+	case s.Type == openapi3.TypeNumber:
+		info.Type = schema.Number
+	case s.Type == openapi3.TypeBoolean:
+		info.Type = schema.Boolean
+	case s.Type == openapi3.TypeArray:
+		info.Type = schema.Array
+		info.ElemInfo = schemaParam(s.Items, depth+1)
+	case s.Type == openapi3.TypeObject || len(s.Properties) > 0:
+		info.Type = schema.Object
+		if depth < maxSchemaDepth && len(s.Properties) > 0 {
+			info.SubParams = make(map[string]*schema.ParameterInfo, len(s.Properties))
+			for name, prop := range s.Properties {
+				if prop != nil && prop.Value != nil && prop.Value.ReadOnly {
+					continue
+				}
+				info.SubParams[name] = schemaParam(prop, depth+1)
+			}
+			for _, name := range s.Required {
+				if sub, ok := info.SubParams[name]; ok {
+					sub.Required = true
+				}
+			}
+		}
+	default:
+		for _, value := range s.Enum {
+			if str, ok := value.(string); ok {
+				info.Enum = append(info.Enum, str)
+			}
+		}
+	}
+	return info
+}
+
+// mergeAllOf combines the members of an allOf schema into one object schema
+func mergeAllOf(s *openapi3.Schema) *openapi3.Schema {
+	merged := &openapi3.Schema{Description: s.Description, Properties: make(openapi3.Schemas)}
+	for _, ref := range s.AllOf {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		member := ref.Value
+		if merged.Type == "" {
+			merged.Type = member.Type
+		}
+		if merged.Description == "" {
+			merged.Description = member.Description
+		}
+		for name, prop := range member.Properties {
+			merged.Properties[name] = prop
+		}
+		merged.Required = append(merged.Required, member.Required...)
+		if member.Items != nil {
+			merged.Items = member.Items
+		}
+	}
+	return merged
+}
+
+// loadSpec reads a spec from a file or an http(s) URL, the URL is returned to resolve relative server URLs.
+// A URL spec can only reference documents of its own origin.
+func loadSpec(spec string, client *http.Client) (*openapi3.T, *url.URL, error) {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = openapi3.ReadFromURIs(openapi3.ReadFromHTTP(client), openapi3.ReadFromFile)
+
+	if u, err := url.Parse(spec); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		// A remote spec must not make the CLI read local files or fetch documents from other servers,
+		// neither by a reference nor by a redirect of the server
+		sameOrigin := func(location *url.URL) error {
+			if location.Scheme != u.Scheme || location.Host != u.Host {
+				return fmt.Errorf("OpenAPI spec %s can only reference documents of the same origin, not %s", spec, location)
+			}
+			return nil
+		}
+		specClient := *client
+		specClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return fmt.Errorf("stopped after 10 redirects")
+			}
+			return sameOrigin(req.URL)
+		}
+		readHTTP := openapi3.ReadFromHTTP(&specClient)
+		loader.ReadFromURIFunc = func(loader *openapi3.Loader, location *url.URL) ([]byte, error) {
+			if err := sameOrigin(location); err != nil {
+				return nil, err
+			}
+			return readHTTP(loader, location)
+		}
+		doc, err := loader.LoadFromURI(u)
+		return doc, u, err
+	}
+
+	// Handle ~ symbol
+	if strings.HasPrefix(spec, "~/") {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get user home directory: %v", err)
+		}
+		spec = strings.Replace(spec, "~", homeDir, 1)
+	}
+	doc, err := loader.LoadFromFile(spec)
+	return doc, nil, err
+}
+
+// serverVarPattern matches the variables of a server URL
+var serverVarPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// serverURL returns the URL requests are sent to, without a trailing slash: the configured base_url, or the first
+// server of the spec with its variables set to their defaults, resolved against the spec URL if relative
+func serverURL(baseURL string, doc *openapi3.T, specURL *url.URL) (string, error) {
+	if baseURL != "" {
+		return strings.TrimRight(baseURL, "/"), nil
+	}
+
+	raw := "/"
+	if len(doc.Servers) > 0 && doc.Servers[0] != nil {
+		server := doc.Servers[0]
+		raw = serverVarPattern.ReplaceAllStringFunc(server.URL, func(match string) string {
+			if variable, ok := server.Variables[match[1:len(match)-1]]; ok && variable != nil {
+				return variable.Default
+			}
+			return match
+		})
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid server URL %q in OpenAPI spec: %v", raw, err)
+	}
+	if !u.IsAbs() {
+		if specURL == nil {
+			return "", fmt.Errorf("OpenAPI spec has no absolute server URL, configure base_url")
+		}
+		u = specURL.ResolveReference(u)
+	}
+	return strings.TrimRight(u.String(), "/"), nil
+}
+
+// toolNamePattern matches the characters that are not allowed in tool names
+var toolNamePattern = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// toolName turns an operation name into a tool name models accept: letters, digits, _ and -, at most 64 characters
+func toolName(name string) string {
+	name = strings.Trim(toolNamePattern.ReplaceAllString(name, "_"), "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// sortedKeys returns the keys of a map in order, so that tools are created in a stable order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
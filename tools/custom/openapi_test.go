@@ -0,0 +1,264 @@
+package custom
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tk103331/eino-cli/config"
+	"gopkg.in/yaml.v3"
+)
+
+// newTestOpenAPITools creates the tools of an openapi tool from the YAML of its config section
+func newTestOpenAPITools(t *testing.T, toolConfig string) ([]*HTTPTool, error) {
+	t.Helper()
+	var cfg config.Tool
+	if err := yaml.Unmarshal([]byte("type: openapi\nconfig:\n  "+strings.ReplaceAll(toolConfig, "\n", "\n  ")), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	tools, err := NewOpenAPITools("tickets", cfg)
+	if err != nil {
+		return nil, err
+	}
+	httpTools := make([]*HTTPTool, len(tools))
+	for i, tool := range tools {
+		httpTools[i] = tool.(*HTTPTool)
+	}
+	return httpTools, nil
+}
+
+func TestOpenAPIToolSelection(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    []string // Tool names in order
+		wantErr string
+	}{
+		{
+			name:   "all operations",
+			config: "spec: testdata/openapi.yaml",
+			want:   []string{"listTickets", "createTicket", "get_tickets_id", "replaceTicket", "addLabels"},
+		},
+		{
+			name:   "operationId and method path pattern",
+			config: "spec: testdata/openapi.yaml\noperations: [createTicket, \"GET /tickets/*\"]",
+			want:   []string{"createTicket", "get_tickets_id"},
+		},
+		{
+			name:   "tags",
+			config: "spec: testdata/openapi.yaml\ntags: [labels]",
+			want:   []string{"addLabels"},
+		},
+		{
+			name:   "operations and tags with prefix",
+			config: "spec: testdata/openapi.yaml\noperations: [\"* /tickets\", addLabels]\ntags: [tickets]\nprefix: \"t.\"",
+			want:   []string{"t_listTickets", "t_createTicket"},
+		},
+		{
+			name:    "no match",
+			config:  "spec: testdata/openapi.yaml\noperations: [deleteTicket]",
+			wantErr: "no operation",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tools, err := newTestOpenAPITools(t, tt.config)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, tool := range tools {
+				names = append(names, tool.info.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("expected tools %v, got %v", tt.want, names)
+			}
+		})
+	}
+}
+
+func TestOpenAPIToolRequests(t *testing.T) {
+	tools, err := newTestOpenAPITools(t, "spec: testdata/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tools[0].httpConfig.URL, "https://eu.tickets.example.com/api/tickets"; got != want {
+		t.Errorf("expected the first server with its defaults, got URL %s", got)
+	}
+
+	tests := []struct {
+		tool       string
+		wantParams string // Declared params in order
+		arguments  string
+		wantMethod string
+		wantPath   string
+		wantQuery  string
+		wantBody   string
+	}{
+		{
+			tool:       "listTickets",
+			wantParams: "status,labels",
+			arguments:  `{"labels":["bug","ui"]}`,
+			wantMethod: "GET",
+			wantPath:   "/api/tickets",
+			wantQuery:  "labels=bug%2Cui",
+		},
+		{
+			// The object body is flattened into params, without the read-only id
+			tool:       "createTicket",
+			wantParams: "priority,title",
+			arguments:  `{"title":"Broken","priority":"2"}`,
+			wantMethod: "POST",
+			wantPath:   "/api/tickets",
+			wantBody:   `{"priority":2,"title":"Broken"}`,
+		},
+		{
+			tool:       "get_tickets_id",
+			wantParams: "id",
+			arguments:  `{"id":"a/b"}`,
+			wantMethod: "GET",
+			wantPath:   "/api/tickets/a%2Fb",
+		},
+		{
+			// The body property id clashes with the path param, so the body stays one param
+			tool:       "replaceTicket",
+			wantParams: "id,body",
+			arguments:  `{"id":"7","body":{"id":"7","title":"Fixed"}}`,
+			wantMethod: "PUT",
+			wantPath:   "/api/tickets/7",
+			wantBody:   `{"id":"7","title":"Fixed"}`,
+		},
+		{
+			tool:       "addLabels",
+			wantParams: "id,body",
+			arguments:  `{"id":"7","body":["bug"]}`,
+			wantMethod: "POST",
+			wantPath:   "/api/tickets/7/labels",
+			wantBody:   `["bug"]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			var method, path, query, body string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				method, path, query, body = r.Method, r.URL.EscapedPath(), r.URL.RawQuery, string(data)
+				w.Write([]byte("ok"))
+			}))
+			defer server.Close()
+
+			tools, err := newTestOpenAPITools(t, "spec: testdata/openapi.yaml\nbase_url: "+server.URL+"/api/")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var tool *HTTPTool
+			for _, candidate := range tools {
+				if candidate.info.Name == tt.tool {
+					tool = candidate
+				}
+			}
+			if tool == nil {
+				t.Fatalf("tool %s was not created", tt.tool)
+			}
+			var params []string
+			for _, param := range tool.config.Params {
+				params = append(params, param.Name)
+			}
+			if strings.Join(params, ",") != tt.wantParams {
+				t.Errorf("expected params %s, got %v", tt.wantParams, params)
+			}
+
+			result, err := tool.InvokableRun(context.Background(), tt.arguments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != "ok" {
+				t.Errorf("expected the response, got %q", result)
+			}
+			if method != tt.wantMethod || path != tt.wantPath {
+				t.Errorf("expected %s %s, got %s %s", tt.wantMethod, tt.wantPath, method, path)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("expected query %q, got %q", tt.wantQuery, query)
+			}
+			if strings.TrimSpace(body) != tt.wantBody {
+				t.Errorf("expected body %s, got %s", tt.wantBody, body)
+			}
+		})
+	}
+}
+
+func TestOpenAPIRemoteSpecRefs(t *testing.T) {
+	other := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	defer other.Close()
+	local, err := filepath.Abs("testdata/schemas.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		ref     string // Reference of the createTicket body schema, empty to load testdata/openapi.yaml
+		wantErr string
+	}{
+		{name: "same origin"},
+		{name: "other origin", ref: other.URL + "/schemas.yaml#/Ticket", wantErr: "same origin"},
+		{name: "local file", ref: "file://" + local + "#/Ticket", wantErr: "same origin"},
+		{name: "same origin redirect", ref: "moved/schemas.yaml#/Ticket"},
+		{name: "redirect to other origin", ref: "redirect/schemas.yaml#/Ticket", wantErr: "same origin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.Handle("/", http.FileServer(http.Dir("testdata")))
+			mux.Handle("/moved/", http.RedirectHandler("/schemas.yaml", http.StatusFound))
+			mux.Handle("/redirect/", http.RedirectHandler(other.URL+"/schemas.yaml", http.StatusFound))
+			mux.HandleFunc("/remote.yaml", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`openapi: 3.0.3
+info:
+  title: Remote
+  version: "1.0"
+paths:
+  /tickets:
+    post:
+      operationId: createTicket
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: "` + tt.ref + `"
+`))
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			spec := server.URL + "/openapi.yaml"
+			if tt.ref != "" {
+				spec = server.URL + "/remote.yaml"
+			}
+			tools, err := newTestOpenAPITools(t, "spec: "+spec+"\noperations: [createTicket]")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tools[0].httpConfig.JSONBody; strings.Join(got, ",") != "priority,title" {
+				t.Errorf("expected the referenced schema to be flattened, got json_body %v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package custom
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// ProgressFunc receives the output of a running tool line by line
+type ProgressFunc func(line string)
+
+type progressKey struct{}
+
+// WithProgress returns a context whose tool runs report their output lines to fn
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// reportProgress sends a line to the progress function of the context, if any
+func reportProgress(ctx context.Context, line string) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		fn(line)
+	}
+}
+
+// outputWindow keeps the head and the tail of a tool output, each half of max bytes, and counts what is dropped
+// in between, so that the model sees how a long output starts and ends
+type outputWindow struct {
+	head    bytes.Buffer
+	tail    []byte
+	max     int
+	dropped int
+}
+
+// add writes p and returns the part that went into the head
+func (w *outputWindow) add(p []byte) []byte {
+	headMax := w.max / 2
+	room := headMax - w.head.Len()
+	if room >= len(p) {
+		w.head.Write(p)
+		return p
+	}
+	var head []byte
+	if room > 0 {
+		head, p = p[:room], p[room:]
+		w.head.Write(head)
+	}
+	w.tail = append(w.tail, p...)
+	if tailMax := w.max - headMax; len(w.tail) > tailMax {
+		w.dropped += len(w.tail) - tailMax
+		w.tail = append(w.tail[:0], w.tail[len(w.tail)-tailMax:]...)
+	}
+	return head
+}
+
+// Write implements io.Writer, it never fails so that the command is not interrupted
+func (w *outputWindow) Write(p []byte) (int, error) {
+	w.add(p)
+	return len(p), nil
+}
+
+// Len returns the number of bytes kept
+func (w *outputWindow) Len() int {
+	return w.head.Len() + len(w.tail)
+}
+
+// rest returns what follows the head: a note about the dropped bytes and the tail
+func (w *outputWindow) rest() string {
+	if w.dropped > 0 {
+		return fmt.Sprintf("\n[... %d bytes omitted ...]\n%s", w.dropped, w.tail)
+	}
+	return string(w.tail)
+}
+
+// String returns the kept output
+func (w *outputWindow) String() string {
+	return w.head.String() + w.rest()
+}
+
+// streamLines reads r line by line, reports every line as progress and sends the head of the output
+// as it arrives; the rest is sent once r is exhausted or fails. Lines longer than the read buffer
+// are reported in pieces, so that output without newlines is never held in memory at once.
+func streamLines(ctx context.Context, r io.Reader, window *outputWindow, sw *schema.StreamWriter[string]) error {
+	reader := bufio.NewReader(r)
+	var err error
+	for {
+		// The slice is only valid until the next read, add and Send copy it
+		line, readErr := reader.ReadSlice('\n')
+		if len(line) > 0 {
+			reportProgress(ctx, string(bytes.TrimRight(line, "\r\n")))
+			if head := window.add(line); len(head) > 0 {
+				if closed := sw.Send(string(head), nil); closed {
+					// The reader is gone, stop reading
+					return nil
+				}
+			}
+		}
+		if readErr == nil || readErr == bufio.ErrBufferFull {
+			continue
+		}
+		if readErr != io.EOF {
+			err = readErr
+		}
+		break
+	}
+	if rest := window.rest(); rest != "" {
+		sw.Send(rest, nil)
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+package custom
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// failingReader returns its data and then fails
+type failingReader struct {
+	r   io.Reader
+	err error
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if err == io.EOF {
+		return n, f.err
+	}
+	return n, err
+}
+
+func TestStreamLines(t *testing.T) {
+	readErr := errors.New("connection reset")
+	long := strings.Repeat("a", 50000) + strings.Repeat("z", 50000)
+	tests := []struct {
+		name         string
+		input        io.Reader
+		max          int
+		want         string
+		wantErr      error
+		wantProgress int // Number of progress reports
+	}{
+		{
+			name:         "lines",
+			input:        strings.NewReader("one\ntwo\r\nthree"),
+			max:          100,
+			want:         "one\ntwo\r\nthree",
+			wantProgress: 3,
+		},
+		{
+			name:         "long line without newline",
+			input:        strings.NewReader(long),
+			max:          20,
+			want:         "aaaaaaaaaa\n[... 99980 bytes omitted ...]\nzzzzzzzzzz",
+			wantProgress: 25, // Pieces of the 4096 bytes read buffer
+		},
+		{
+			name:         "read error",
+			input:        &failingReader{r: strings.NewReader("one\n" + long), err: readErr},
+			max:          20,
+			want:         "one\naaaaaa\n[... 99984 bytes omitted ...]\nzzzzzzzzzz",
+			wantErr:      readErr,
+			wantProgress: 26,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := 0
+			ctx := WithProgress(context.Background(), func(line string) {
+				if len(line) > 4096 {
+					t.Errorf("expected progress in pieces of the read buffer, got %d bytes", len(line))
+				}
+				progress++
+			})
+			sr, sw := schema.Pipe[string](1)
+			done := make(chan error, 1)
+			go func() {
+				defer sw.Close()
+				done <- streamLines(ctx, tt.input, &outputWindow{max: tt.max}, sw)
+			}()
+
+			var got strings.Builder
+			for {
+				chunk, err := sr.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				got.WriteString(chunk)
+			}
+			if err := <-done; !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("expected output %q, got %q", tt.want, got.String())
+			}
+			if progress != tt.wantProgress {
+				t.Errorf("expected %d progress reports, got %d", tt.wantProgress, progress)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+//go:build linux
+
+package custom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// sandboxArg makes the binary act as the sandbox helper when it is re-executed
+	sandboxArg = "__eino-cli-sandbox"
+	// sandboxEnv passes the sandbox settings to the helper
+	sandboxEnv = "EINO_CLI_SANDBOX"
+)
+
+// RunSandboxHelper makes the binary act as the sandbox helper when an exec tool re-executed it: inside the new
+// namespaces it applies the restrictions and replaces itself with the command, otherwise it returns.
+// Programs using exec tools with a sandbox must call it first in main.
+func RunSandboxHelper() {
+	if len(os.Args) > 2 && os.Args[1] == sandboxArg {
+		if err := sandboxExec(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "sandbox: %v\n", err)
+			os.Exit(126)
+		}
+	}
+}
+
+// validateSandbox checks the sandbox settings when the tool is created
+func validateSandbox(sb *SandboxConfig) error {
+	for _, dir := range sb.ReadOnly {
+		if !filepath.IsAbs(dir) {
+			return fmt.Errorf("sandbox read_only directory must be absolute: %s", dir)
+		}
+	}
+	return nil
+}
+
+// sandboxCommand creates a command that runs argv in new user and mount namespaces, and in a new network
+// namespace without interfaces unless network access is allowed. The binary re-executes itself as helper.
+func sandboxCommand(ctx context.Context, sb *SandboxConfig, argv []string) (*exec.Cmd, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate executable for sandbox: %w", err)
+	}
+	spec, err := json.Marshal(sb)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, self, append([]string{sandboxArg}, argv...)...)
+	cmd.Env = append(os.Environ(), sandboxEnv+"="+string(spec))
+
+	flags := uintptr(syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS)
+	if !sb.Network {
+		flags |= syscall.CLONE_NEWNET
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags: flags,
+		// Keep the current user inside the namespace, so that file ownership is unchanged
+		UidMappings:                []syscall.SysProcIDMap{{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1}},
+		GidMappings:                []syscall.SysProcIDMap{{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1}},
+		GidMappingsEnableSetgroups: false,
+		Pdeathsig:                  syscall.SIGKILL,
+	}
+	return cmd, nil
+}
+
+// sandboxExec runs in the helper: it remounts the read-only directories, sets the resource limits and
+// executes the command
+func sandboxExec(argv []string) error {
+	var sb SandboxConfig
+	if err := json.Unmarshal([]byte(os.Getenv(sandboxEnv)), &sb); err != nil {
+		return fmt.Errorf("invalid settings: %w", err)
+	}
+	if err := os.Unsetenv(sandboxEnv); err != nil {
+		return err
+	}
+
+	// Keep mount changes inside the namespace
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("failed to make mounts private: %w", err)
+	}
+	for _, dir := range sb.ReadOnly {
+		if err := bindReadOnly(dir); err != nil {
+			return err
+		}
+	}
+
+	limits := []struct {
+		resource int
+		value    int
+		unit     uint64
+	}{
+		{unix.RLIMIT_AS, sb.MaxMemory, 1 << 20},
+		{unix.RLIMIT_CPU, sb.MaxCPUTime, 1},
+		{unix.RLIMIT_NPROC, sb.MaxProcesses, 1},
+		{unix.RLIMIT_FSIZE, sb.MaxFileSize, 1 << 20},
+	}
+	for _, limit := range limits {
+		if limit.value <= 0 {
+			continue
+		}
+		value := uint64(limit.value) * limit.unit
+		if err := unix.Setrlimit(limit.resource, &unix.Rlimit{Cur: value, Max: value}); err != nil {
+			return fmt.Errorf("failed to set resource limit: %w", err)
+		}
+	}
+
+	path, err := exec.LookPath(argv[0])
+	if err != nil {
+		return err
+	}
+	return unix.Exec(path, argv, os.Environ())
+}
+
+// bindReadOnly makes a directory read-only by bind-mounting it onto itself. Flags such as nosuid that are
+// locked on the original mount must be kept when remounting.
+func bindReadOnly(dir string) error {
+	if err := unix.Mount(dir, dir, "", unix.MS_BIND|unix.MS_REC, ""); err != nil {
+		return fmt.Errorf("failed to bind %s: %w", dir, err)
+	}
+	var st unix.Statfs_t
+	if err := unix.Statfs(dir, &st); err != nil {
+		return fmt.Errorf("failed to stat %s: %w", dir, err)
+	}
+	flags := uintptr(unix.MS_BIND | unix.MS_REMOUNT | unix.MS_RDONLY)
+	for _, f := range []struct{ st, ms uintptr }{
+		{unix.ST_NOSUID, unix.MS_NOSUID},
+		{unix.ST_NODEV, unix.MS_NODEV},
+		{unix.ST_NOEXEC, unix.MS_NOEXEC},
+		{unix.ST_NOATIME, unix.MS_NOATIME},
+		{unix.ST_NODIRATIME, unix.MS_NODIRATIME},
+		{unix.ST_RELATIME, unix.MS_RELATIME},
+	} {
+		if uintptr(st.Flags)&f.st != 0 {
+			flags |= f.ms
+		}
+	}
+	if err := unix.Mount("", dir, "", flags, ""); err != nil {
+		return fmt.Errorf("failed to make %s read-only: %w", dir, err)
+	}
+	return nil
+}
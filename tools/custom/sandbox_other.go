@@ -0,0 +1,22 @@
+//go:build !linux
+
+package custom
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+)
+
+// RunSandboxHelper does nothing, the sandbox helper is only needed on Linux
+func RunSandboxHelper() {}
+
+// validateSandbox rejects the sandbox, restricted mode relies on Linux namespaces
+func validateSandbox(sb *SandboxConfig) error {
+	return errors.New("exec tool sandbox is only supported on Linux")
+}
+
+// sandboxCommand is never called since validateSandbox fails
+func sandboxCommand(ctx context.Context, sb *SandboxConfig, argv []string) (*exec.Cmd, error) {
+	return nil, errors.New("exec tool sandbox is only supported on Linux")
+}
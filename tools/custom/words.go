@@ -0,0 +1,129 @@
+package custom
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/tk103331/eino-cli/config"
+)
+
+// splitWords splits a command template into words before it is rendered, so that a rendered value
+// always stays one argument. Words are separated by unquoted whitespace; single quotes, double quotes
+// and backslashes work as in a POSIX shell, and template actions such as {{ .path }} are kept whole.
+func splitWords(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case strings.HasPrefix(s[i:], "{{"):
+			end, err := actionEnd(s, i)
+			if err != nil {
+				return nil, err
+			}
+			word.WriteString(s[i:end])
+			inWord = true
+			i = end
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated single quote")
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			inWord = true
+			i += end + 2
+		case c == '"':
+			i++
+			for {
+				if i >= len(s) {
+					return nil, errors.New("unterminated double quote")
+				}
+				if s[i] == '"' {
+					i++
+					break
+				}
+				if strings.HasPrefix(s[i:], "{{") {
+					end, err := actionEnd(s, i)
+					if err != nil {
+						return nil, err
+					}
+					word.WriteString(s[i:end])
+					i = end
+					continue
+				}
+				if s[i] == '\\' && i+1 < len(s) && strings.ContainsRune(`"\$`+"`", rune(s[i+1])) {
+					i++
+				}
+				word.WriteByte(s[i])
+				i++
+			}
+			inWord = true
+		case c == '\\' && i+1 < len(s):
+			word.WriteByte(s[i+1])
+			inWord = true
+			i += 2
+		case unicode.IsSpace(rune(c)):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+			i++
+		default:
+			word.WriteByte(c)
+			inWord = true
+			i++
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// actionEnd returns the index after the template action starting at i, skipping "}}" inside its strings
+func actionEnd(s string, i int) (int, error) {
+	var quote byte
+	for j := i + 2; j < len(s); j++ {
+		switch {
+		case quote != 0:
+			if s[j] == '\\' && quote == '"' {
+				j++
+			} else if s[j] == quote {
+				quote = 0
+			}
+		case s[j] == '"' || s[j] == '`' || s[j] == '\'':
+			quote = s[j]
+		case strings.HasPrefix(s[j:], "}}"):
+			return j + 2, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated template action at %q", s[i:])
+}
+
+// shellQuote quotes a value for use in a shell command line, available as the quote template function
+func shellQuote(value interface{}) string {
+	return "'" + strings.ReplaceAll(fmt.Sprint(value), "'", `'\''`) + "'"
+}
+
+// templateArgs returns the data templates are rendered with. Declared params that were not given and null values
+// become empty strings, since text/template prints missing and nil map values as <no value>; templates fail on
+// names that are not declared.
+func templateArgs(params []config.ToolParam, args map[string]interface{}) map[string]interface{} {
+	data := make(map[string]interface{}, len(params)+len(args))
+	for _, param := range params {
+		data[param.Name] = ""
+	}
+	for key, value := range args {
+		if value != nil {
+			data[key] = value
+		} else {
+			data[key] = ""
+		}
+	}
+	return data
+}
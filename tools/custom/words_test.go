@@ -0,0 +1,58 @@
+package custom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr string // Expected substring of the error
+	}{
+		{name: "empty", input: "  ", want: nil},
+		{name: "whitespace", input: "ls  -l\t/tmp\n", want: []string{"ls", "-l", "/tmp"}},
+		{name: "single quotes", input: `echo 'a  b' 'it"s'`, want: []string{"echo", "a  b", `it"s`}},
+		{name: "double quotes", input: `echo "a  b" "it's"`, want: []string{"echo", "a  b", "it's"}},
+		{name: "escapes in double quotes", input: `echo "a\"b" "c\\d" "e\$f" "g\n"`, want: []string{"echo", `a"b`, `c\d`, "e$f", `g\n`}},
+		{name: "backslashes", input: `echo a\ b \'c\' d\\e`, want: []string{"echo", "a b", "'c'", `d\e`}},
+		{name: "trailing backslash", input: `echo a\`, want: []string{"echo", `a\`}},
+		{name: "adjacent parts", input: `pre'fix'"ed"x`, want: []string{"prefixedx"}},
+		{name: "empty quotes", input: `echo '' ""`, want: []string{"echo", "", ""}},
+		{name: "action", input: "grep {{ .pattern }} {{.path}}", want: []string{"grep", "{{ .pattern }}", "{{.path}}"}},
+		{name: "action with spaces and quotes", input: `echo {{ printf "%s }} %s" .a 'x' }}`, want: []string{"echo", `{{ printf "%s }} %s" .a 'x' }}`}},
+		{name: "action with raw string", input: "echo {{ printf `}}` }}", want: []string{"echo", "{{ printf `}}` }}"}},
+		{name: "action inside a word", input: "--name={{.name}}.txt", want: []string{"--name={{.name}}.txt"}},
+		{name: "action inside double quotes", input: `echo "hello {{ .name }}!"`, want: []string{"echo", "hello {{ .name }}!"}},
+		{name: "quoted action", input: "echo {{quote .name}}", want: []string{"echo", "{{quote .name}}"}},
+		{name: "unterminated single quote", input: "echo 'abc", wantErr: "unterminated single quote"},
+		{name: "unterminated double quote", input: `echo "abc`, wantErr: "unterminated double quote"},
+		{name: "unterminated action", input: "echo {{ .name", wantErr: "unterminated template action"},
+		{name: "unterminated action in double quotes", input: `echo "{{ .name"`, wantErr: "unterminated template action"},
+		{name: "unterminated string in action", input: `echo {{ printf "}} }}`, wantErr: "unterminated template action"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitWords(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v (words %q)", tt.wantErr, err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("expected %q, got %q", tt.want, got)
+				}
+			}
+		})
+	}
+}
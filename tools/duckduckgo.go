@@ -10,6 +10,10 @@ import (
 	"github.com/tk103331/eino-cli/config"
 )
 
+func init() {
+	Register("duckduckgo", NewDuckDuckGoTool)
+}
+
 // NewDuckDuckGoTool creates DuckDuckGo search tool
 func NewDuckDuckGoTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
 	ctx := context.Background()
@@ -1,52 +1,81 @@
 package tools
 
 import (
+	"context"
 	"fmt"
-	"github.com/tk103331/eino-cli/tools/custom"
-	"strings"
 
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/tk103331/eino-cli/config"
 )
 
-// CreateTool creates tool instance based on configuration
+// CreateTool creates tool instance based on configuration, using the constructor registered for its type.
+// The tool is wrapped with its approval policy. Bundle types must be created with CreateTools.
 func CreateTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
-	switch strings.ToLower(cfg.Type) {
-	case "customhttp":
-		return custom.NewHTTPTool(name, cfg)
-	case "customexec":
-		return custom.NewExecTool(name, cfg)
-	case "bingsearch":
-		return NewBingSearchTool(name, cfg)
-	case "browseruse":
-		return NewBrowserUseTool(name, cfg)
-	case "commandline":
-		return NewCommandLineTool(name, cfg)
-	case "duckduckgo":
-		return NewDuckDuckGoTool(name, cfg)
-	case "googlesearch":
-		return NewGoogleSearchTool(name, cfg)
-	case "httprequest":
-		return NewHTTPRequestTool(name, cfg)
-	case "sequentialthinking":
-		return NewSequentialThinkingTool(name, cfg)
-	case "wikipedia":
-		return NewWikipediaTool(name, cfg)
-	default:
+	toolType, ok := Lookup(cfg.Type)
+	if !ok {
 		return nil, fmt.Errorf("unsupported tool type: %s", cfg.Type)
 	}
+	if toolType.New == nil {
+		return nil, fmt.Errorf("tool type %s creates a bundle of tools", cfg.Type)
+	}
+	t, err := toolType.New(name, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return WithApproval(name, cfg.Approval, cfg.ApprovalRules, t)
 }
 
-// CreateToolsFromConfig creates all tools from configuration
+// CreateTools creates the tools configured under name: the tool itself, or every tool of a bundle type.
+// Tools of a bundle share its approval policy.
+func CreateTools(name string, cfg config.Tool) ([]tool.InvokableTool, error) {
+	toolType, ok := Lookup(cfg.Type)
+	if !ok {
+		return nil, fmt.Errorf("unsupported tool type: %s", cfg.Type)
+	}
+	if toolType.NewBundle == nil {
+		t, err := CreateTool(name, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return []tool.InvokableTool{t}, nil
+	}
+
+	bundle, err := toolType.NewBundle(name, cfg)
+	if err != nil {
+		return nil, err
+	}
+	for i, t := range bundle {
+		info, err := t.Info(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		if bundle[i], err = WithApproval(info.Name, cfg.Approval, cfg.ApprovalRules, t); err != nil {
+			return nil, err
+		}
+	}
+	return bundle, nil
+}
+
+// CreateToolsFromConfig creates all tools from configuration, keyed by tool name
 func CreateToolsFromConfig(cfg *config.Config) (map[string]tool.InvokableTool, error) {
 	tools := make(map[string]tool.InvokableTool)
 
 	for name, toolCfg := range cfg.Tools {
-		toolInstance, err := CreateTool(name, toolCfg)
+		toolInstances, err := CreateTools(name, toolCfg)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create tool %s: %v", name, err)
 		}
-		tools[name] = toolInstance
+		if toolType, _ := Lookup(toolCfg.Type); toolType.NewBundle == nil {
+			tools[name] = toolInstances[0]
+			continue
+		}
+		for _, toolInstance := range toolInstances {
+			info, err := toolInstance.Info(context.Background())
+			if err != nil {
+				return nil, fmt.Errorf("failed to create tool %s: %v", name, err)
+			}
+			tools[info.Name] = toolInstance
+		}
 	}
 
 	return tools, nil
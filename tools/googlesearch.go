@@ -8,6 +8,10 @@ import (
 	"github.com/tk103331/eino-cli/config"
 )
 
+func init() {
+	Register("googlesearch", NewGoogleSearchTool, "api_key", "search_engine_id")
+}
+
 // NewGoogleSearchTool creates Google search tool
 func NewGoogleSearchTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
 	ctx := context.Background()
@@ -11,6 +11,10 @@ import (
 	"github.com/tk103331/eino-cli/config"
 )
 
+func init() {
+	Register("httprequest", NewHTTPRequestTool)
+}
+
 // NewHTTPRequestTool creates HTTP request tool
 func NewHTTPRequestTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
 	ctx := context.Background()
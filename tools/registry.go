@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/tk103331/eino-cli/config"
+)
+
+// Constructor creates a tool instance from its configuration
+type Constructor func(name string, cfg config.Tool) (tool.InvokableTool, error)
+
+// BundleConstructor creates the tools of a bundle type from its configuration, such as one tool
+// per operation of an OpenAPI spec
+type BundleConstructor func(name string, cfg config.Tool) ([]tool.InvokableTool, error)
+
+// ToolType describes a registered tool type
+type ToolType struct {
+	Name      string
+	Required  []string // Config attributes the tool cannot work without
+	New       Constructor
+	NewBundle BundleConstructor // Set instead of New by bundle types
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]ToolType)
+)
+
+// Register makes a tool type available to CreateTool, usually from the init function of the file implementing it.
+// Programs embedding eino-cli can register their own tool types before loading the configuration.
+// Type names are case-insensitive; registering the same type twice panics.
+func Register(typ string, constructor Constructor, required ...string) {
+	if constructor == nil {
+		panic("tools: Register requires a type and a constructor")
+	}
+	register(ToolType{Name: typ, Required: required, New: constructor})
+}
+
+// RegisterBundle makes a bundle type available to CreateTools. A tool configured with a bundle type
+// stands for all tools its constructor creates, so agents reference them by the one name.
+func RegisterBundle(typ string, constructor BundleConstructor, required ...string) {
+	if constructor == nil {
+		panic("tools: RegisterBundle requires a type and a constructor")
+	}
+	register(ToolType{Name: typ, Required: required, NewBundle: constructor})
+}
+
+func register(t ToolType) {
+	t.Name = strings.ToLower(t.Name)
+	if t.Name == "" {
+		panic("tools: Register requires a type and a constructor")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[t.Name]; exists {
+		panic(fmt.Sprintf("tools: tool type %s registered twice", t.Name))
+	}
+	registry[t.Name] = t
+}
+
+// Lookup returns a registered tool type
+func Lookup(typ string) (ToolType, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	t, ok := registry[strings.ToLower(typ)]
+	return t, ok
+}
+
+// Types returns the registered tool types in alphabetical order
+func Types() []ToolType {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	types := make([]ToolType, 0, len(registry))
+	for _, t := range registry {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
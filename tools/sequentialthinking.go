@@ -6,6 +6,10 @@ import (
 	"github.com/tk103331/eino-cli/config"
 )
 
+func init() {
+	Register("sequentialthinking", NewSequentialThinkingTool)
+}
+
 // NewSequentialThinkingTool creates sequential thinking tool
 func NewSequentialThinkingTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
 	// Sequential Thinking tool doesn't need special configuration, create directly
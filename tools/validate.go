@@ -0,0 +1,27 @@
+package tools
+
+import (
+	"github.com/tk103331/eino-cli/config"
+)
+
+// ValidateTool checks the tool type and its required settings without creating the tool
+func ValidateTool(cfg *config.Config, name string, diags *config.Diagnostics) {
+	toolCfg := cfg.Tools[name]
+	if err := ValidateApproval(toolCfg.Approval, toolCfg.ApprovalRules); err != nil {
+		diags.Add(cfg, []string{"tools", name, "approval"}, "%v", err)
+	}
+	if toolCfg.Type == "" {
+		// Reported by config.Validate
+		return
+	}
+	toolType, ok := Lookup(toolCfg.Type)
+	if !ok {
+		diags.Add(cfg, []string{"tools", name, "type"}, "unsupported tool type: %s", toolCfg.Type)
+		return
+	}
+	for _, key := range toolType.Required {
+		if value, exists := toolCfg.Config[key]; !exists || value.IsEmpty() || value.String() == "" {
+			diags.Add(cfg, []string{"tools", name, "config"}, "%s tool %q must configure %s attribute", toolCfg.Type, name, key)
+		}
+	}
+}
@@ -9,6 +9,10 @@ import (
 	"github.com/tk103331/eino-cli/config"
 )
 
+func init() {
+	Register("wikipedia", NewWikipediaTool)
+}
+
 // NewWikipediaTool creates Wikipedia tool
 func NewWikipediaTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
 	ctx := context.Background()
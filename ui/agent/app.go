@@ -2,8 +2,10 @@ package agent
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"sync/atomic"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/tool"
@@ -12,6 +14,7 @@ import (
 	"github.com/tk103331/eino-cli/config"
 	"github.com/tk103331/eino-cli/logger"
 	"github.com/tk103331/eino-cli/models"
+	"github.com/tk103331/eino-cli/session"
 	"github.com/tk103331/eino-cli/tools"
 )
 
@@ -21,7 +24,11 @@ type AgentApp struct {
 	program   *tea.Program
 	model     *ViewModel
 	agent     agent.Agent
+	history   *agent.Conversation
+	session   *session.Session
+	store     *session.Store
 	ctx       context.Context
+	busy      atomic.Bool // Whether a turn is running, messages are rejected until it ends
 }
 
 // ChatApp represents the chat application structure (merged from chat functionality)
@@ -35,10 +42,17 @@ type ChatApp struct {
 	model        *ViewModel
 	chatModel    model.ToolCallingChatModel
 	reactAgent   agent.Agent
+	history      *agent.Conversation
+	session      *session.Session
+	store        *session.Store
+	busy         atomic.Bool // Whether a turn is running, messages are rejected until it ends
 }
 
-// NewAgentApp creates a new Agent application
-func NewAgentApp(agentName string) (*AgentApp, error) {
+// errTurnInProgress is returned for a message sent while the previous turn is still running
+var errTurnInProgress = errors.New("the previous response is still in progress")
+
+// NewAgentApp creates a new Agent application, the conversation is restored from and saved to sess
+func NewAgentApp(agentName string, sess *session.Session) (*AgentApp, error) {
 	logger.Info("UI-AGENT", fmt.Sprintf("Creating agent app: %s", agentName))
 
 	cfg := config.GetConfig()
@@ -60,8 +74,8 @@ func NewAgentApp(agentName string) (*AgentApp, error) {
 	// Create Agent factory
 	factory := agent.NewFactory(cfg)
 
-	// Create Agent instance
-	agentInstance, err := factory.CreateAgent(agentName)
+	// Create Agent instance, running with the session's model if it overrides the configured one
+	agentInstance, err := factory.CreateAgentWithModel(agentName, sess.Model)
 	if err != nil {
 		logger.Error("UI-AGENT", fmt.Sprintf("Failed to create agent: %v", err))
 		return nil, fmt.Errorf("failed to create Agent: %v", err)
@@ -72,22 +86,27 @@ func NewAgentApp(agentName string) (*AgentApp, error) {
 	app := &AgentApp{
 		agentName: agentName,
 		agent:     agentInstance,
+		history:   agent.NewConversation(sess.History()...),
+		session:   sess,
+		store:     session.NewStore(""),
 		ctx:       context.Background(),
 	}
 
 	// Create Agent model, passing in the callback function for sending messages
 	agentModel := NewViewModel(app.sendMessage)
+	agentModel.LoadHistory(sess.History())
 	app.model = agentModel
 
-	// Create Bubble Tea program
+	// Create Bubble Tea program, tool calls that need approval are asked in it
 	app.program = tea.NewProgram(*agentModel, tea.WithAltScreen())
+	tools.SetApprover(newApprover(app.program))
 
 	logger.Info("UI-AGENT", fmt.Sprintf("Agent app created successfully: %s", agentName))
 	return app, nil
 }
 
-// NewChatApp creates a new chat application (merged from chat functionality)
-func NewChatApp(modelName string, tools []string, system string) *ChatApp {
+// NewChatApp creates a new chat application (merged from chat functionality), the conversation is restored from and saved to sess
+func NewChatApp(modelName string, toolNames []string, system string, sess *session.Session) *ChatApp {
 	cfg := config.GetConfig()
 	factory := models.NewFactory(cfg)
 	agentFactory := agent.NewFactory(cfg)
@@ -96,16 +115,21 @@ func NewChatApp(modelName string, tools []string, system string) *ChatApp {
 		modelFactory: factory,
 		agentFactory: agentFactory,
 		modelName:    modelName,
-		tools:        tools,
+		tools:        toolNames,
 		system:       system,
+		history:      agent.NewConversation(sess.History()...),
+		session:      sess,
+		store:        session.NewStore(""),
 	}
 
 	// Create chat model, passing in the callback function for sending messages
 	chatModel := NewViewModel(app.sendMessage)
+	chatModel.LoadHistory(sess.History())
 	app.model = chatModel
 
-	// Create Bubble Tea program
+	// Create Bubble Tea program, tool calls that need approval are asked in it
 	app.program = tea.NewProgram(*chatModel, tea.WithAltScreen())
+	tools.SetApprover(newApprover(app.program))
 
 	return app
 }
@@ -126,62 +150,71 @@ func (app *ChatApp) Run() error {
 func (app *AgentApp) sendMessage(message string) error {
 	logger.Info("UI-AGENT", fmt.Sprintf("Sending message: %s", truncateForLog(message)))
 
-	// Get Agent configuration
-	cfg := config.GetConfig()
-	agentConfig := cfg.Agents[app.agentName]
-
-	// Build message list
-	var messages []*schema.Message
-
-	// Add system message (if any)
-	if agentConfig.System != "" {
-		messages = append(messages, schema.SystemMessage(agentConfig.System))
-		logger.Debug("UI-AGENT", fmt.Sprintf("Using system prompt: %s", truncateForLog(agentConfig.System)))
+	// Only one turn may use the conversation history at a time
+	if !app.busy.CompareAndSwap(false, true) {
+		app.program.Send(ErrorMsg(errTurnInProgress.Error()))
+		return errTurnInProgress
 	}
 
-	// Add user message
-	messages = append(messages, schema.UserMessage(message))
+	// Remember history length so a failed turn can be rolled back
+	turnStart := app.history.Len()
+
+	// Add user message to conversation history, the agent prepends its own system prompt
+	app.history.Append(schema.UserMessage(message))
 
 	// Handle conversation in goroutine to avoid blocking UI
-	go app.processConversation(messages)
+	go app.processConversation(turnStart)
 
 	return nil
 }
 
 // sendMessage sends a message to AI model (for ChatApp use)
 func (app *ChatApp) sendMessage(message string) error {
-	// If there are tool configurations, use ReactAgent's ChatWithCallback method
-	if len(app.tools) > 0 {
-		return app.sendMessageWithAgent(message)
+	// Only one turn may use the conversation history at a time, the turn goroutine clears the flag
+	if !app.busy.CompareAndSwap(false, true) {
+		app.program.Send(ErrorMsg(errTurnInProgress.Error()))
+		return errTurnInProgress
 	}
 
-	// Otherwise use the original model direct call method
-	return app.sendMessageWithModel(message)
+	// If there are tool configurations, use ReactAgent, otherwise call the model directly
+	send := app.sendMessageWithModel
+	if len(app.tools) > 0 {
+		send = app.sendMessageWithAgent
+	}
+	err := send(message)
+	if err != nil {
+		app.busy.Store(false)
+	}
+	return err
 }
 
 // processConversation handles conversation (using streaming output)
-func (app *AgentApp) processConversation(messages []*schema.Message) {
-	logger.Info("UI-AGENT", "Starting conversation processing")
-
-	// Get the last user message as prompt
-	var prompt string
-	for i := len(messages) - 1; i >= 0; i-- {
-		if messages[i].Role == schema.User {
-			prompt = messages[i].Content
-			break
-		}
-	}
+func (app *AgentApp) processConversation(turnStart int) {
+	logger.Info("UI-AGENT", fmt.Sprintf("Starting conversation processing with %d history messages", app.history.Len()))
+	defer app.busy.Store(false)
+
+	startUsageTurn()
+	defer sendUsage(app.program)
 
-	if prompt == "" {
-		logger.Error("UI-AGENT", "User message not found in conversation")
-		app.program.Send(ErrorMsg("User message not found"))
+	// Use Agent's ChatStreamWithHistory method for streaming conversation
+	logger.Info("UI-AGENT", "Calling agent ChatStreamWithHistory")
+	toolCallback := withSessionRecording(app.session, newToolCallback(app.program))
+	turn, err := app.agent.ChatStreamWithHistory(app.ctx, app.history.Messages(), newChunkCallback(app.program), toolCallback)
+	if err != nil {
+		logger.Error("UI-AGENT", fmt.Sprintf("AI response error: %v", err))
+		app.history.Truncate(turnStart)
+		app.program.Send(ErrorMsg(fmt.Sprintf("AI response error: %v", err)))
 		return
 	}
 
-	logger.Info("UI-AGENT", fmt.Sprintf("Processing prompt: %s", truncateForLog(prompt)))
+	app.history.Append(turn...)
+	saveSession(app.store, app.session, app.history)
+	logger.Info("UI-AGENT", "ChatStreamWithHistory completed successfully")
+}
 
-	// Create tool call callback function
-	toolCallback := func(msg interface{}) {
+// newToolCallback creates the tool call callback that forwards agent tool events to the UI
+func newToolCallback(program *tea.Program) func(interface{}) {
+	return func(msg interface{}) {
 		logger.Debug("UI-CALLBACK", fmt.Sprintf("Received: %T", msg))
 
 		switch v := msg.(type) {
@@ -194,20 +227,25 @@ func (app *AgentApp) processConversation(messages []*schema.Message) {
 				case "start":
 					logger.Info("UI-TOOL", fmt.Sprintf("Starting: %s", v.Name))
 					logger.Debug("UI-TOOL", fmt.Sprintf("Arguments: %s", v.Arguments))
-					app.program.Send(ToolStartMsg{
+					program.Send(ToolStartMsg{
 						Name:      v.Name,
 						Arguments: v.Arguments,
 					})
+				case "progress":
+					program.Send(ToolProgressMsg{
+						Name: v.Name,
+						Line: v.Result,
+					})
 				case "end":
 					logger.Info("UI-TOOL", fmt.Sprintf("Completed: %s", v.Name))
 					logger.Debug("UI-TOOL", fmt.Sprintf("Result: %s", truncateForLog(v.Result)))
-					app.program.Send(ToolEndMsg{
+					program.Send(ToolEndMsg{
 						Name:   v.Name,
 						Result: v.Result,
 					})
 				case "error":
 					logger.Error("UI-TOOL", fmt.Sprintf("Tool %s error: %s", v.Name, v.Error))
-					app.program.Send(ErrorMsg(fmt.Sprintf("Tool %s error: %s", v.Name, v.Error)))
+					program.Send(ErrorMsg(fmt.Sprintf("Tool %s error: %s", v.Name, v.Error)))
 				}
 			} else {
 				logger.Debug("UI-CALLBACK", fmt.Sprintf("Filtered internal component: %s", v.Name))
@@ -222,7 +260,7 @@ func (app *AgentApp) processConversation(messages []*schema.Message) {
 			if !isInternal {
 				logger.Info("UI-TOOL", fmt.Sprintf("Starting (legacy): %s", v.Name))
 				logger.Debug("UI-TOOL", fmt.Sprintf("Arguments: %s", v.Arguments))
-				app.program.Send(ToolStartMsg{
+				program.Send(ToolStartMsg{
 					Name:      v.Name,
 					Arguments: v.Arguments,
 				})
@@ -237,7 +275,7 @@ func (app *AgentApp) processConversation(messages []*schema.Message) {
 			if !isInternal {
 				logger.Info("UI-TOOL", fmt.Sprintf("Completed (legacy): %s", v.Name))
 				logger.Debug("UI-TOOL", fmt.Sprintf("Result: %s", truncateForLog(v.Result)))
-				app.program.Send(ToolEndMsg{
+				program.Send(ToolEndMsg{
 					Name:   v.Name,
 					Result: v.Result,
 				})
@@ -246,34 +284,40 @@ func (app *AgentApp) processConversation(messages []*schema.Message) {
 			logger.Warn("UI-CALLBACK", fmt.Sprintf("Unknown callback type: %T", msg))
 			if errMsg, ok := msg.(string); ok {
 				logger.Error("UI-CALLBACK", fmt.Sprintf("Error message: %s", errMsg))
-				app.program.Send(ErrorMsg(errMsg))
+				program.Send(ErrorMsg(errMsg))
 			}
 		}
 	}
+}
+
+// newApprover asks the user to approve tool calls in the UI, the tool waits until the user decides
+func newApprover(program *tea.Program) tools.Approver {
+	return func(ctx context.Context, req tools.ApprovalRequest) tools.ApprovalDecision {
+		reply := make(chan tools.ApprovalDecision, 1)
+		program.Send(ApprovalRequestMsg{Request: req, Reply: reply})
+		select {
+		case decision := <-reply:
+			return decision
+		case <-ctx.Done():
+			return tools.ApprovalDecision{Reason: ctx.Err().Error()}
+		}
+	}
+}
 
-	// Create streaming content callback function
-	chunkCallback := func(chunk *agent.StreamChunk) {
+// newChunkCallback creates the streaming content callback that forwards agent output to the UI
+func newChunkCallback(program *tea.Program) func(*agent.StreamChunk) {
+	return func(chunk *agent.StreamChunk) {
 		switch chunk.Type {
 		case "content":
 			if chunk.Content != "" {
-				app.program.Send(StreamChunkMsg(chunk.Content))
+				program.Send(StreamChunkMsg(chunk.Content))
 			} else {
-				app.program.Send(StreamEndMsg{})
+				program.Send(StreamEndMsg{})
 			}
 		case "error":
-			app.program.Send(ErrorMsg(chunk.Content))
+			program.Send(ErrorMsg(chunk.Content))
 		}
 	}
-
-	// Use Agent's ChatStream method for streaming conversation
-	logger.Info("UI-AGENT", "Calling agent ChatStream")
-	err := app.agent.ChatStream(app.ctx, prompt, chunkCallback, toolCallback)
-	if err != nil {
-		logger.Error("UI-AGENT", fmt.Sprintf("AI response error: %v", err))
-		app.program.Send(ErrorMsg(fmt.Sprintf("AI response error: %v", err)))
-	} else {
-		logger.Info("UI-AGENT", "ChatStream completed successfully")
-	}
 }
 
 // sendMessageWithAgent sends messages using ReactAgent, supporting tool call callbacks (for ChatApp use)
@@ -295,68 +339,27 @@ func (app *ChatApp) sendMessageWithAgent(message string) error {
 		app.reactAgent = reactAgent
 	}
 
-	// Run Agent in background and get response
+	// Add user message to conversation history
+	turnStart := app.history.Len()
+	app.history.Append(schema.UserMessage(message))
+
+	// Run Agent in background and stream response over the whole conversation
 	go func() {
+		defer app.busy.Store(false)
 		ctx := context.Background()
+		startUsageTurn()
+		defer sendUsage(app.program)
 
-		// Create tool call callback function
-		callback := func(data interface{}) {
-			switch v := data.(type) {
-			case string:
-				// Streaming content
-				app.program.Send(StreamChunkMsg(v))
-			case map[string]interface{}:
-				// Tool call information
-				if toolType, ok := v["type"].(string); ok {
-					switch toolType {
-					case "tool_start":
-						if toolName, ok := v["tool_name"].(string); ok {
-							var inputStr string
-							if input := v["input"]; input != nil {
-								if inputBytes, err := json.Marshal(input); err == nil {
-									inputStr = string(inputBytes)
-								}
-							}
-							app.program.Send(ToolStartMsg{
-								Name:      toolName,
-								Arguments: inputStr,
-							})
-						}
-					case "tool_end":
-						if toolName, ok := v["tool_name"].(string); ok {
-							var outputStr string
-							if output := v["output"]; output != nil {
-								if outputBytes, err := json.Marshal(output); err == nil {
-									outputStr = string(outputBytes)
-								}
-							}
-							app.program.Send(ToolEndMsg{
-								Name:   toolName,
-								Result: outputStr,
-							})
-						}
-					case "tool_error":
-						if toolName, ok := v["tool_name"].(string); ok {
-							if errorMsg, ok := v["error"].(string); ok {
-								app.program.Send(ErrorMsg(fmt.Sprintf("Tool %s execution error: %s", toolName, errorMsg)))
-							}
-						}
-					}
-				}
-			}
-		}
-
-		// Use Agent's ChatWithCallback method to generate response
-		response, err := app.reactAgent.ChatWithCallback(ctx, message, callback)
+		toolCallback := withSessionRecording(app.session, newToolCallback(app.program))
+		turn, err := app.reactAgent.ChatStreamWithHistory(ctx, app.history.Messages(), newChunkCallback(app.program), toolCallback)
 		if err != nil {
+			app.history.Truncate(turnStart)
 			app.program.Send(ErrorMsg(fmt.Sprintf("AI response error: %v", err)))
 			return
 		}
 
-		// Send complete response
-		if response != "" {
-			app.program.Send(ResponseMsg(response))
-		}
+		app.history.Append(turn...)
+		saveSession(app.store, app.session, app.history)
 	}()
 
 	return nil
@@ -403,26 +406,38 @@ func (app *ChatApp) sendMessageWithModel(message string) error {
 		app.chatModel = chatModel
 	}
 
+	// Add user message to conversation history
+	turnStart := app.history.Len()
+	app.history.Append(schema.UserMessage(message))
+
 	// Run model in background and get streaming response
 	go func() {
+		defer app.busy.Store(false)
 		ctx := context.Background()
+		startUsageTurn()
+		defer sendUsage(app.program)
 
-		// Create message list, including optional system prompt
+		// Create message list, including optional system prompt and previous turns
 		var messages []*schema.Message
 		if app.system != "" {
 			messages = append(messages, schema.SystemMessage(app.system))
 		}
-		messages = append(messages, schema.UserMessage(message))
+		messages = append(messages, app.history.Messages()...)
 
-		// Start conversation loop, handling tool calls
-		app.processConversation(ctx, messages)
+		// Start conversation loop, handling tool calls; roll back the turn if it fails
+		if !app.processConversation(ctx, messages) {
+			app.history.Truncate(turnStart)
+			return
+		}
+		saveSession(app.store, app.session, app.history)
 	}()
 
 	return nil
 }
 
-// processConversation handles conversation loop, including tool calls (for ChatApp use)
-func (app *ChatApp) processConversation(ctx context.Context, messages []*schema.Message) {
+// processConversation handles conversation loop, including tool calls (for ChatApp use).
+// Messages produced during the loop are appended to the conversation history, returns false if the turn failed
+func (app *ChatApp) processConversation(ctx context.Context, messages []*schema.Message) bool {
 	maxIterations := 10 // Prevent infinite loops
 	iteration := 0
 
@@ -433,7 +448,7 @@ func (app *ChatApp) processConversation(ctx context.Context, messages []*schema.
 		streamReader, err := app.chatModel.Stream(ctx, messages)
 		if err != nil {
 			app.program.Send(ErrorMsg(fmt.Sprintf("AI response error: %v", err)))
-			return
+			return false
 		}
 
 		// Handle streaming response
@@ -448,7 +463,7 @@ func (app *ChatApp) processConversation(ctx context.Context, messages []*schema.
 				if err.Error() != "EOF" && err.Error() != "io: read/write on closed pipe" {
 					app.program.Send(ErrorMsg(fmt.Sprintf("Streaming response error: %v", err)))
 					streamReader.Close()
-					return
+					return false
 				}
 				break
 			}
@@ -481,17 +496,20 @@ func (app *ChatApp) processConversation(ctx context.Context, messages []*schema.
 			}
 
 			// Add assistant message to message history
+			assistantMessage.Content = fullContent
 			messages = append(messages, assistantMessage)
 
 			// Execute tool calls
 			toolResults, err := app.executeToolCalls(ctx, assistantMessage.ToolCalls)
 			if err != nil {
 				app.program.Send(ErrorMsg(fmt.Sprintf("Tool execution error: %v", err)))
-				return
+				return false
 			}
 
 			// Add tool results to message history
 			messages = append(messages, toolResults...)
+			app.history.Append(assistantMessage)
+			app.history.Append(toolResults...)
 
 			// Continue to next round of conversation
 			continue
@@ -500,13 +518,13 @@ func (app *ChatApp) processConversation(ctx context.Context, messages []*schema.
 			if fullContent != "" {
 				app.program.Send(ResponseMsg(fullContent))
 			}
-			break
+			app.history.Append(schema.AssistantMessage(fullContent, nil))
+			return true
 		}
 	}
 
-	if iteration >= maxIterations {
-		app.program.Send(ErrorMsg("Maximum iterations reached, stopping conversation"))
-	}
+	app.program.Send(ErrorMsg("Maximum iterations reached, stopping conversation"))
+	return false
 }
 
 // executeToolCalls executes tool calls (for ChatApp use)
@@ -553,8 +571,11 @@ func (app *ChatApp) executeToolCalls(ctx context.Context, toolCalls []schema.Too
 		app.program.Send(StreamChunkMsg(fmt.Sprintf("\n🔧 Calling tool: %s\nArguments: %s\n", toolName, arguments)))
 
 		// Execute tool
+		app.session.StartToolCall(toolName, arguments)
 		result, err := toolInstance.InvokableRun(ctx, arguments)
 		if err != nil {
+			app.session.EndToolCall(toolName, "", err.Error())
+
 			// Tool execution failed, return error message
 			errorMsg := fmt.Sprintf("Tool execution failed: %v", err)
 			toolMessage := schema.ToolMessage(errorMsg, toolCall.ID, schema.WithToolName(toolName))
@@ -564,6 +585,7 @@ func (app *ChatApp) executeToolCalls(ctx context.Context, toolCalls []schema.Too
 			app.program.Send(StreamChunkMsg(fmt.Sprintf("❌ Tool execution failed: %v\n", err)))
 		} else {
 			// Tool execution succeeded, return result
+			app.session.EndToolCall(toolName, result, "")
 			toolMessage := schema.ToolMessage(result, toolCall.ID, schema.WithToolName(toolName))
 			toolMessages = append(toolMessages, toolMessage)
 
@@ -594,13 +616,13 @@ func (app *ChatApp) createTools() ([]tool.InvokableTool, error) {
 			return nil, fmt.Errorf("tool configuration does not exist: %s", toolName)
 		}
 
-		// Create tool instance
-		toolInstance, err := tools.CreateTool(toolName, toolCfg)
+		// Create tool instances, a bundle adds all of its tools
+		created, err := tools.CreateTools(toolName, toolCfg)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create tool %s: %v", toolName, err)
 		}
 
-		toolInstances = append(toolInstances, toolInstance)
+		toolInstances = append(toolInstances, created...)
 	}
 
 	return toolInstances, nil
@@ -608,19 +630,7 @@ func (app *ChatApp) createTools() ([]tool.InvokableTool, error) {
 
 // isInternalComponent determines if it is an internal component that should not be displayed to users
 func isInternalComponent(name string) bool {
-	// Filter out internal ReAct components that don't represent actual tool calls
-	// but still show actual tool invocations
-	internalComponents := []string{
-		"ChatModel",
-		"Tools", // This is the ReAct tools orchestrator node, not actual tool calls
-	}
-
-	for _, component := range internalComponents {
-		if name == component {
-			return true
-		}
-	}
-	return false
+	return agent.IsInternalNode(name)
 }
 
 // truncateForLog truncates text for logging to avoid huge log entries
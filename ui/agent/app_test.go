@@ -0,0 +1,340 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+	"github.com/tk103331/eino-cli/agent"
+	"github.com/tk103331/eino-cli/config"
+	"github.com/tk103331/eino-cli/models"
+	"github.com/tk103331/eino-cli/session"
+	"github.com/tk103331/eino-cli/tools"
+)
+
+func init() {
+	tools.Register("test_prefix", newPrefixTool, "prefix")
+	tools.Register("test_block", newBlockTool)
+}
+
+// prefixTool answers with its configured prefix followed by the call arguments
+type prefixTool struct {
+	name   string
+	prefix string
+}
+
+func newPrefixTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
+	prefix := cfg.Config["prefix"]
+	return &prefixTool{name: name, prefix: prefix.String()}, nil
+}
+
+func (t *prefixTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: t.name, Desc: "Echoes the arguments after " + t.prefix}, nil
+}
+
+func (t *prefixTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	return t.prefix + argumentsInJSON, nil
+}
+
+// blockStarted and blockRelease let a test hold a turn inside the block tool
+var blockStarted, blockRelease chan struct{}
+
+// blockTool signals blockStarted and answers once blockRelease is closed
+type blockTool struct {
+	name string
+}
+
+func newBlockTool(name string, cfg config.Tool) (tool.InvokableTool, error) {
+	return &blockTool{name: name}, nil
+}
+
+func (t *blockTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: t.name, Desc: "Blocks until released"}, nil
+}
+
+func (t *blockTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	blockStarted <- struct{}{}
+	<-blockRelease
+	return "released", nil
+}
+
+// recordingView collects the messages the app sends to the UI
+type recordingView struct {
+	mu   *sync.Mutex
+	msgs *[]tea.Msg
+}
+
+func (v recordingView) Init() tea.Cmd { return nil }
+
+func (v recordingView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	*v.msgs = append(*v.msgs, msg)
+	return v, nil
+}
+
+func (v recordingView) View() string { return "" }
+
+// newTestChatApp creates a chat app with the tools upper and lower, whose model replays the given interactions.
+// The returned function stops the UI and returns the messages it received.
+func newTestChatApp(t *testing.T, interactions []models.Interaction) (*ChatApp, func() []tea.Msg) {
+	t.Helper()
+	dir := t.TempDir()
+	cassette := filepath.Join(dir, "cassette.json")
+	if err := (&models.Cassette{Interactions: interactions}).Save(cassette); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(dir, "config.yml")
+	data := fmt.Sprintf(`providers:
+  fake:
+    type: mock
+    cassette: %q
+models:
+  scripted:
+    provider: fake
+    model: scripted
+tools:
+  upper:
+    type: test_prefix
+    config:
+      prefix: "upper:"
+  lower:
+    type: test_prefix
+    config:
+      prefix: "lower:"
+  block:
+    type: test_block
+`, cassette)
+	if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := &ChatApp{
+		modelFactory: models.NewFactory(cfg),
+		modelName:    "scripted",
+		tools:        []string{"upper", "lower"},
+		history:      agent.NewConversation(),
+		session:      session.New("chat", "", "scripted"),
+		store:        session.NewStore(filepath.Join(dir, "sessions")),
+	}
+	chatModel, err := app.modelFactory.CreateChatModel(context.Background(), app.modelName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	toolInstances, err := app.createTools()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var toolInfos []*schema.ToolInfo
+	for _, toolInstance := range toolInstances {
+		info, err := toolInstance.Info(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		toolInfos = append(toolInfos, info)
+	}
+	if app.chatModel, err = chatModel.WithTools(toolInfos); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu   sync.Mutex
+		msgs []tea.Msg
+	)
+	app.program = tea.NewProgram(recordingView{mu: &mu, msgs: &msgs},
+		tea.WithInput(nil), tea.WithOutput(io.Discard), tea.WithoutRenderer(), tea.WithoutSignalHandler())
+	done := make(chan error, 1)
+	go func() {
+		_, err := app.program.Run()
+		done <- err
+	}()
+	return app, func() []tea.Msg {
+		app.program.Quit()
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		return msgs
+	}
+}
+
+func TestChatAppProcessConversation(t *testing.T) {
+	toolCall := func(name string) models.Interaction {
+		return models.Interaction{Response: &models.MockMessage{ToolCalls: []models.MockToolCall{{ID: "call_1", Name: name, Arguments: `{"text":"hi"}`}}}}
+	}
+	tests := []struct {
+		name         string
+		interactions []models.Interaction
+		wantOK       bool
+		wantHistory  []string // Role and content of the messages appended to the history
+		wantUI       string   // Expected substring of a message sent to the UI
+	}{
+		{
+			name:         "reply",
+			interactions: []models.Interaction{{Response: &models.MockMessage{Content: "hello there", Chunks: []string{"hello ", "there"}}}},
+			wantOK:       true,
+			wantHistory:  []string{"assistant:hello there"},
+			wantUI:       "hello there",
+		},
+		{
+			name: "routes the call to lower",
+			interactions: []models.Interaction{
+				toolCall("lower"),
+				{Match: `^lower:\{"text":"hi"\}$`, Response: &models.MockMessage{Content: "all done"}},
+			},
+			wantOK:      true,
+			wantHistory: []string{"assistant:", `tool:lower:{"text":"hi"}`, "assistant:all done"},
+			wantUI:      "Calling tool: lower",
+		},
+		{
+			name: "routes the call to upper",
+			interactions: []models.Interaction{
+				toolCall("upper"),
+				{Match: `^upper:\{"text":"hi"\}$`, Response: &models.MockMessage{Content: "all done"}},
+			},
+			wantOK:      true,
+			wantHistory: []string{"assistant:", `tool:upper:{"text":"hi"}`, "assistant:all done"},
+			wantUI:      "Calling tool: upper",
+		},
+		{
+			name: "unknown tool",
+			interactions: []models.Interaction{
+				toolCall("missing"),
+				{Match: "does not exist", Response: &models.MockMessage{Content: "sorry"}},
+			},
+			wantOK:      true,
+			wantHistory: []string{"assistant:", "tool:Tool 'missing' does not exist", "assistant:sorry"},
+			wantUI:      "Tool 'missing' does not exist",
+		},
+		{
+			name:         "model error",
+			interactions: []models.Interaction{{Error: "status code: 500"}},
+			wantUI:       "status code: 500",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, stop := newTestChatApp(t, tt.interactions)
+			ok := app.processConversation(context.Background(), []*schema.Message{schema.UserMessage("hello")})
+			msgs := stop()
+
+			if ok != tt.wantOK {
+				t.Fatalf("expected processConversation to return %v", tt.wantOK)
+			}
+			var history []string
+			for _, msg := range app.history.Messages() {
+				history = append(history, string(msg.Role)+":"+msg.Content)
+			}
+			if strings.Join(history, "\n") != strings.Join(tt.wantHistory, "\n") {
+				t.Errorf("expected history %q, got %q", tt.wantHistory, history)
+			}
+			var ui []string
+			for _, msg := range msgs {
+				switch msg := msg.(type) {
+				case ResponseMsg:
+					ui = append(ui, string(msg))
+				case StreamChunkMsg:
+					ui = append(ui, string(msg))
+				case ErrorMsg:
+					ui = append(ui, string(msg))
+				}
+			}
+			if !strings.Contains(strings.Join(ui, "\n"), tt.wantUI) {
+				t.Errorf("expected the UI to show %q, got %q", tt.wantUI, ui)
+			}
+		})
+	}
+}
+
+func TestChatAppRejectsMessageDuringTurn(t *testing.T) {
+	app, stop := newTestChatApp(t, []models.Interaction{
+		{Response: &models.MockMessage{ToolCalls: []models.MockToolCall{{ID: "call_1", Name: "block", Arguments: `{}`}}}},
+		{Match: "^released$", Response: &models.MockMessage{Content: "all done"}},
+	})
+	app.tools = append(app.tools, "block")
+	blockStarted, blockRelease = make(chan struct{}), make(chan struct{})
+
+	if err := app.sendMessage("first"); err != nil {
+		t.Fatal(err)
+	}
+	<-blockStarted
+	if err := app.sendMessage("second"); err != errTurnInProgress {
+		t.Errorf("expected the second message to be rejected, got %v", err)
+	}
+	close(blockRelease)
+	for deadline := time.Now().Add(5 * time.Second); app.busy.Load(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("the turn did not finish")
+		}
+	}
+	msgs := stop()
+
+	var history []string
+	for _, msg := range app.history.Messages() {
+		history = append(history, string(msg.Role)+":"+msg.Content)
+	}
+	want := []string{"user:first", "assistant:", "tool:released", "assistant:all done"}
+	if strings.Join(history, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected history %q, got %q", want, history)
+	}
+	saved, err := app.store.Load(app.session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(saved.Messages); got != len(want) {
+		t.Errorf("expected %d saved messages, got %d", len(want), got)
+	}
+	rejected := false
+	for _, msg := range msgs {
+		if msg, ok := msg.(ErrorMsg); ok && string(msg) == errTurnInProgress.Error() {
+			rejected = true
+		}
+	}
+	if !rejected {
+		t.Error("expected the UI to show the rejected message")
+	}
+}
+
+func TestChatAppRollsBackFailedTurn(t *testing.T) {
+	app, stop := newTestChatApp(t, []models.Interaction{{Error: "status code: 500"}})
+	// Continue a restored session
+	restored := []*schema.Message{schema.UserMessage("hello"), schema.AssistantMessage("hi", nil)}
+	app.session.SetMessages(restored)
+	app.history = agent.NewConversation(app.session.History()...)
+
+	if err := app.sendMessage("next"); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); app.busy.Load(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("the turn did not finish")
+		}
+	}
+	stop()
+
+	var history []string
+	for _, msg := range app.history.Messages() {
+		history = append(history, string(msg.Role)+":"+msg.Content)
+	}
+	if want := []string{"user:hello", "assistant:hi"}; strings.Join(history, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected the failed turn to be rolled back to %q, got %q", want, history)
+	}
+	if _, err := app.store.Load(app.session.ID); !errors.Is(err, session.ErrSessionNotFound) {
+		t.Errorf("expected the failed turn not to be saved, got %v", err)
+	}
+}
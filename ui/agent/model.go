@@ -10,6 +10,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/tk103331/eino-cli/tools"
+	"github.com/tk103331/eino-cli/usage"
 )
 
 // MessageType represents the type of a message
@@ -42,6 +44,7 @@ type Message struct {
 	Result     string     // Tool result (only used for tool messages)
 	StartTime  int64      // Tool start time (Unix timestamp, only used for tool messages)
 	EndTime    int64      // Tool end time (Unix timestamp, only used for tool messages)
+	Progress   []string   // Latest output lines of a running tool (only used for tool messages)
 }
 
 // ViewModel is the model for the Agent interface
@@ -59,8 +62,14 @@ type ViewModel struct {
 	renderer         *glamour.TermRenderer // Markdown renderer
 	scrollOffset     int                   // Scroll offset for up/down key scrolling (line-based)
 	renderedLines    []string              // Cached rendered lines for efficient scrolling
+	usage            *UsageMsg             // Token usage of the last turn and the whole session
+	approvals        []ApprovalRequestMsg  // Tool calls waiting for approval, the first one is shown
+	editingArgs      bool                  // Whether the arguments of the shown approval are being edited in the input
 }
 
+// maxProgressLines is the number of output lines shown under a running tool
+const maxProgressLines = 5
+
 // Message type definitions
 type ResponseMsg string
 type StreamChunkMsg string
@@ -70,10 +79,24 @@ type ToolStartMsg struct {
 	Name      string
 	Arguments string
 }
+type ToolProgressMsg struct {
+	Name string
+	Line string
+}
 type ToolEndMsg struct {
 	Name   string
 	Result string
 }
+type UsageMsg struct {
+	Turn    usage.Usage
+	Session usage.Usage
+}
+
+// ApprovalRequestMsg asks the user to approve a tool call, the decision is sent to Reply
+type ApprovalRequestMsg struct {
+	Request tools.ApprovalRequest
+	Reply   chan<- tools.ApprovalDecision
+}
 
 // NewViewModel creates a new ViewModel
 func NewViewModel(onSendMsg func(string) error) *ViewModel {
@@ -306,6 +329,9 @@ func (m ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if len(m.approvals) > 0 {
+			return m.updateApproval(msg)
+		}
 		if m.isWaiting {
 			// Only allow exit when waiting for response
 			switch msg.Type {
@@ -467,6 +493,22 @@ func (m ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		})
 		return m, nil
 
+	case ToolProgressMsg:
+		// Output line of a running tool - keep the latest lines on the waiting tool message
+		for i := len(m.messages) - 1; i >= 0; i-- {
+			if m.messages[i].Type == ToolStartMessage &&
+				m.messages[i].Name == msg.Name &&
+				m.messages[i].ToolStatus == ToolWaiting {
+				progress := append(m.messages[i].Progress, msg.Line)
+				if len(progress) > maxProgressLines {
+					progress = progress[len(progress)-maxProgressLines:]
+				}
+				m.messages[i].Progress = progress
+				break
+			}
+		}
+		return m, nil
+
 	case ToolEndMsg:
 		// Tool execution ended - find and update the existing tool message
 		toolResult := strings.TrimSpace(msg.Result)
@@ -543,7 +585,8 @@ func (m ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.messages[i].ToolStatus = newStatus
 				m.messages[i].Result = toolResult
 				m.messages[i].EndTime = time.Now().Unix() // Record end time
-				return m, nil                             // Exit early, don't create a new message
+				m.messages[i].Progress = nil
+				return m, nil // Exit early, don't create a new message
 			}
 		}
 
@@ -565,6 +608,17 @@ func (m ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		})
 		return m, nil
 
+	case ApprovalRequestMsg:
+		// Tool call waiting for approval, queued behind earlier requests
+		m.approvals = append(m.approvals, msg)
+		m.scrollOffset = 0
+		return m, nil
+
+	case UsageMsg:
+		// Token usage update after a turn completes
+		m.usage = &msg
+		return m, nil
+
 	case ErrorMsg:
 		// Error message - directly display all error messages (filtering handled at application layer)
 		errorText := string(msg)
@@ -623,7 +677,10 @@ func (m ViewModel) View() string {
 	// Add status indicator to header
 	statusIndicator := "● Ready"
 	statusColor := successColor
-	if m.isWaiting {
+	if len(m.approvals) > 0 {
+		statusIndicator = "● Awaiting approval"
+		statusColor = warningColor
+	} else if m.isWaiting {
 		statusIndicator = "● Thinking..."
 		statusColor = warningColor
 	} else if m.errorMsg != "" {
@@ -636,6 +693,20 @@ func (m ViewModel) View() string {
 			Foreground(lipgloss.Color(statusColor)).
 			Render(statusIndicator))
 
+	// Add token usage to header once available
+	if m.usage != nil {
+		usageText := fmt.Sprintf("🪙 %s tokens", formatTokens(m.usage.Session.TotalTokens))
+		if m.usage.Session.Cost > 0 {
+			usageText += fmt.Sprintf(" $%.4f", m.usage.Session.Cost)
+		}
+		if m.width > 80 {
+			usageText += fmt.Sprintf(" (last turn %s)", formatTokens(m.usage.Turn.TotalTokens))
+		}
+		headerContent += " " + lipgloss.NewStyle().
+			Foreground(lipgloss.Color(mutedColor)).
+			Render(usageText)
+	}
+
 	// Update rendered lines cache
 	m.updateRenderedLines()
 
@@ -700,7 +771,6 @@ func (m ViewModel) View() string {
 	}
 
 	inputText := inputPrompt + m.input + charCount
-	inputArea := inputStyle.Render(inputText)
 
 	// Build enhanced help information
 	helpItems := []string{
@@ -710,6 +780,27 @@ func (m ViewModel) View() string {
 		"Home/End" + " → " + "Top/Bottom",
 	}
 
+	// A pending approval replaces the input box
+	if len(m.approvals) > 0 {
+		request := m.approvals[0].Request
+		inputStyle = inputStyle.BorderForeground(lipgloss.Color(warningColor))
+		if m.editingArgs {
+			inputText = fmt.Sprintf("✏️  Arguments for %s: %s", request.Tool, m.input)
+			helpItems = []string{"Enter → Approve with edited arguments", "Esc → Cancel editing", "Ctrl+C → Deny and quit"}
+		} else {
+			arguments := request.Arguments
+			if len(arguments) > 300 {
+				arguments = arguments[:297] + "..."
+			}
+			inputText = fmt.Sprintf("🔐 Allow tool %s to run?\n📝 Arguments: %s", request.Tool, arguments)
+			if len(m.approvals) > 1 {
+				inputText += fmt.Sprintf("\n(%d more waiting)", len(m.approvals)-1)
+			}
+			helpItems = []string{"y → Approve", "n → Deny", "e → Edit arguments", "Ctrl+C → Deny and quit"}
+		}
+	}
+	inputArea := inputStyle.Render(inputText)
+
 	// Add scroll hint if applicable
 	if len(m.messages) > maxLines {
 		helpItems = append(helpItems, "PageUp/Down → Faster scroll")
@@ -724,6 +815,61 @@ func (m ViewModel) View() string {
 	return fmt.Sprintf("%s\n%s\n\n%s\n%s", header, messageArea, inputArea, helpArea)
 }
 
+// updateApproval handles keys while a tool call waits for approval
+func (m ViewModel) updateApproval(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyCtrlC {
+		for _, approval := range m.approvals {
+			approval.Reply <- tools.ApprovalDecision{Reason: "denied by user"}
+		}
+		m.approvals = nil
+		return m, tea.Quit
+	}
+
+	if m.editingArgs {
+		switch msg.Type {
+		case tea.KeyEnter:
+			if !json.Valid([]byte(m.input)) {
+				m.errorMsg = "Edited arguments are not valid JSON"
+				return m, nil
+			}
+			m.errorMsg = ""
+			m = m.resolveApproval(tools.ApprovalDecision{Approved: true, Arguments: m.input})
+		case tea.KeyEsc:
+			m.editingArgs = false
+			m.input = ""
+		case tea.KeyBackspace:
+			if len(m.input) > 0 {
+				m.input = m.input[:len(m.input)-1]
+			}
+		case tea.KeySpace:
+			m.input += " "
+		case tea.KeyRunes:
+			m.input += string(msg.Runes)
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "y", "Y":
+		m = m.resolveApproval(tools.ApprovalDecision{Approved: true})
+	case "n", "N", "esc":
+		m = m.resolveApproval(tools.ApprovalDecision{Reason: "denied by user"})
+	case "e", "E":
+		m.editingArgs = true
+		m.input = m.approvals[0].Request.Arguments
+	}
+	return m, nil
+}
+
+// resolveApproval answers the shown approval and moves on to the next one
+func (m ViewModel) resolveApproval(decision tools.ApprovalDecision) ViewModel {
+	m.approvals[0].Reply <- decision
+	m.approvals = m.approvals[1:]
+	m.editingArgs = false
+	m.input = ""
+	return m
+}
+
 // formatToolCallContent generates formatted content for tool calls with simplified display
 func (m *ViewModel) formatToolCallContent(msg Message) string {
 	var sections []string
@@ -779,6 +925,13 @@ func (m *ViewModel) formatToolCallContent(msg Message) string {
 		}
 	} else if msg.ToolStatus == ToolWaiting {
 		sections = append(sections, "⌛ Processing...")
+		// Show the latest output of the running tool
+		for _, line := range msg.Progress {
+			if len(line) > 200 {
+				line = line[:197] + "..."
+			}
+			sections = append(sections, "  │ "+line)
+		}
 	}
 
 	return strings.Join(sections, "\n")
@@ -846,6 +999,14 @@ func (m *ViewModel) extractErrorMessage(toolResult string) string {
 	return ""
 }
 
+// formatTokens formats a token count in compact form, e.g. 1.2k
+func formatTokens(n int) string {
+	if n >= 1000 {
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
 // renderMarkdown renders markdown content - same as chat interface
 func (m *ViewModel) renderMarkdown(content string) string {
 	if m.renderer == nil {
@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cloudwego/eino/schema"
+	"github.com/tk103331/eino-cli/agent"
+	"github.com/tk103331/eino-cli/logger"
+	"github.com/tk103331/eino-cli/session"
+	"github.com/tk103331/eino-cli/usage"
+)
+
+// withSessionRecording wraps a tool callback so that tool calls are recorded in the session
+func withSessionRecording(sess *session.Session, callback func(interface{})) func(interface{}) {
+	return func(msg interface{}) {
+		if info, ok := msg.(agent.ToolCallInfo); ok && !isInternalComponent(info.Name) {
+			switch info.Type {
+			case "start":
+				sess.StartToolCall(info.Name, info.Arguments)
+			case "end":
+				sess.EndToolCall(info.Name, info.Result, "")
+			case "error":
+				sess.EndToolCall(info.Name, "", info.Error)
+			}
+		}
+		callback(msg)
+	}
+}
+
+// saveSession stores the conversation history in the session and writes it to disk
+func saveSession(store *session.Store, sess *session.Session, history *agent.Conversation) {
+	sess.SetMessages(history.Messages())
+	if err := store.Save(sess); err != nil {
+		logger.Error("UI-SESSION", fmt.Sprintf("Failed to save session %s: %v", sess.ID, err))
+		return
+	}
+	logger.Debug("UI-SESSION", fmt.Sprintf("Saved session %s with %d messages", sess.ID, history.Len()))
+}
+
+// startUsageTurn resets the per-turn token usage before a new turn
+func startUsageTurn() {
+	if tracker := usage.GetGlobalTracker(); tracker != nil {
+		tracker.StartTurn()
+	}
+}
+
+// sendUsage reports the token usage of the finished turn to the UI
+func sendUsage(program *tea.Program) {
+	if tracker := usage.GetGlobalTracker(); tracker != nil {
+		program.Send(UsageMsg{
+			Turn:    tracker.Turn(),
+			Session: tracker.Session(),
+		})
+	}
+}
+
+// LoadHistory fills the view with messages from a previous conversation
+func (m *ViewModel) LoadHistory(messages []*schema.Message) {
+	for _, msg := range messages {
+		switch msg.Role {
+		case schema.User:
+			m.messages = append(m.messages, Message{
+				Type:    UserMessage,
+				Content: msg.Content,
+			})
+		case schema.Assistant:
+			if msg.Content != "" {
+				m.messages = append(m.messages, Message{
+					Type:    AssistantMessage,
+					Content: msg.Content,
+				})
+			}
+		case schema.Tool:
+			m.messages = append(m.messages, Message{
+				Type:       ToolStartMessage,
+				Name:       msg.ToolName,
+				ToolStatus: ToolSuccess,
+				Result:     msg.Content,
+			})
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package usage
+
+import "fmt"
+
+// String formats usage for display, e.g. "1,234 tokens (1,000 in / 234 out) $0.0123"
+func (u Usage) String() string {
+	s := fmt.Sprintf("%s tokens (%s in / %s out)",
+		formatCount(u.TotalTokens), formatCount(u.PromptTokens), formatCount(u.CompletionTokens))
+	if u.Cost > 0 {
+		s += fmt.Sprintf(" $%.4f", u.Cost)
+	}
+	return s
+}
+
+// formatCount formats a number with thousands separators
+func formatCount(n int) string {
+	s := fmt.Sprintf("%d", n)
+	if n < 0 {
+		return s
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
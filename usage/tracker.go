@@ -0,0 +1,236 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	ucb "github.com/cloudwego/eino/utils/callbacks"
+	"github.com/tk103331/eino-cli/config"
+)
+
+// Usage represents accumulated token usage and cost
+type Usage struct {
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	Cost             float64 `json:"cost,omitempty"`
+}
+
+// add accumulates another usage into u
+func (u *Usage) add(other Usage) {
+	u.Calls += other.Calls
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+	u.Cost += other.Cost
+}
+
+// Tracker sums token usage reported by model callbacks per call, per turn and per session
+type Tracker struct {
+	mu      sync.Mutex
+	idle    *sync.Cond // Signaled when no stream is pending
+	pending int        // Streams whose usage is still being read
+	pricing map[string]*config.Pricing
+	session Usage
+	turn    Usage
+	models  map[string]*Usage
+}
+
+// NewTracker creates a new Tracker, pricing is taken from the model configuration
+func NewTracker(cfg *config.Config) *Tracker {
+	t := &Tracker{
+		pricing: make(map[string]*config.Pricing),
+		models:  make(map[string]*Usage),
+	}
+	t.idle = sync.NewCond(&t.mu)
+	if cfg != nil {
+		for _, modelCfg := range cfg.Models {
+			// Callbacks report the provider model ID, so pricing is keyed by it only
+			if modelCfg.Pricing == nil || modelCfg.Model == "" {
+				continue
+			}
+			t.pricing[modelCfg.Model] = modelCfg.Pricing
+		}
+	}
+	return t
+}
+
+// Handler returns the callback handler that records model token usage
+func (t *Tracker) Handler() callbacks.Handler {
+	return ucb.NewHandlerHelper().ChatModel(&ucb.ModelCallbackHandler{
+		OnEnd: func(ctx context.Context, info *callbacks.RunInfo, output *model.CallbackOutput) context.Context {
+			t.record(modelName(info, output), output.TokenUsage)
+			return ctx
+		},
+		OnEndWithStreamOutput: func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[*model.CallbackOutput]) context.Context {
+			// Consume the stream copy in background so streaming to the caller is not blocked
+			t.begin()
+			go func() {
+				defer t.done()
+				defer output.Close()
+
+				name := modelName(info, nil)
+				var tokenUsage *model.TokenUsage
+				for {
+					chunk, err := output.Recv()
+					if err != nil {
+						if !errors.Is(err, io.EOF) {
+							return
+						}
+						break
+					}
+					if chunk == nil {
+						continue
+					}
+					if chunk.Config != nil && chunk.Config.Model != "" {
+						name = chunk.Config.Model
+					}
+					// Providers report usage on the final chunk(s), keep the latest one
+					if chunk.TokenUsage != nil {
+						tokenUsage = chunk.TokenUsage
+					}
+				}
+				t.record(name, tokenUsage)
+			}()
+			return ctx
+		},
+	}).Handler()
+}
+
+// begin marks a stream as pending until its usage is recorded
+func (t *Tracker) begin() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending++
+}
+
+// done marks a pending stream as finished
+func (t *Tracker) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending--
+	if t.pending == 0 {
+		t.idle.Broadcast()
+	}
+}
+
+// lockIdle waits until no stream is pending and returns with the lock held
+func (t *Tracker) lockIdle() {
+	t.mu.Lock()
+	for t.pending > 0 {
+		t.idle.Wait()
+	}
+}
+
+// StartTurn resets the per-turn counters
+func (t *Tracker) StartTurn() {
+	t.lockIdle()
+	defer t.mu.Unlock()
+	t.turn = Usage{}
+}
+
+// Turn returns the usage since the last StartTurn
+func (t *Tracker) Turn() Usage {
+	t.lockIdle()
+	defer t.mu.Unlock()
+	return t.turn
+}
+
+// Session returns the usage since the tracker was created
+func (t *Tracker) Session() Usage {
+	t.lockIdle()
+	defer t.mu.Unlock()
+	return t.session
+}
+
+// ByModel returns the session usage broken down by model
+func (t *Tracker) ByModel() map[string]Usage {
+	t.lockIdle()
+	defer t.mu.Unlock()
+	result := make(map[string]Usage, len(t.models))
+	for name, u := range t.models {
+		result[name] = *u
+	}
+	return result
+}
+
+// ModelNames returns the names of the models used in the session in sorted order
+func (t *Tracker) ModelNames() []string {
+	t.lockIdle()
+	defer t.mu.Unlock()
+	names := make([]string, 0, len(t.models))
+	for name := range t.models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// record adds the usage of a single model call
+func (t *Tracker) record(name string, tokenUsage *model.TokenUsage) {
+	call := Usage{Calls: 1}
+	if tokenUsage != nil {
+		call.PromptTokens = tokenUsage.PromptTokens
+		call.CompletionTokens = tokenUsage.CompletionTokens
+		call.TotalTokens = tokenUsage.TotalTokens
+		if call.TotalTokens == 0 {
+			call.TotalTokens = call.PromptTokens + call.CompletionTokens
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if pricing, ok := t.pricing[name]; ok {
+		call.Cost = pricing.Cost(call.PromptTokens, call.CompletionTokens)
+	}
+
+	t.session.add(call)
+	t.turn.add(call)
+	if _, ok := t.models[name]; !ok {
+		t.models[name] = &Usage{}
+	}
+	t.models[name].add(call)
+}
+
+// modelName determines the model name of a callback
+func modelName(info *callbacks.RunInfo, output *model.CallbackOutput) string {
+	if output != nil && output.Config != nil && output.Config.Model != "" {
+		return output.Config.Model
+	}
+	if info != nil && info.Name != "" {
+		return info.Name
+	}
+	return "unknown"
+}
+
+// Global usage tracker instance
+var (
+	globalTracker *Tracker
+	trackerMu     sync.RWMutex
+)
+
+// InitializeGlobalTracker creates the global tracker and registers it as a global callback handler.
+// It must be called once before any model is invoked.
+func InitializeGlobalTracker(cfg *config.Config) *Tracker {
+	trackerMu.Lock()
+	defer trackerMu.Unlock()
+
+	globalTracker = NewTracker(cfg)
+	callbacks.AppendGlobalHandlers(globalTracker.Handler())
+	return globalTracker
+}
+
+// GetGlobalTracker gets the global usage tracker, returns nil if not initialized
+func GetGlobalTracker() *Tracker {
+	trackerMu.RLock()
+	defer trackerMu.RUnlock()
+	return globalTracker
+}
@@ -0,0 +1,27 @@
+package usage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+)
+
+func TestTrackerByModel(t *testing.T) {
+	tracker := NewTracker(nil)
+	for _, name := range []string{"gpt-4o", "claude-3", "qwen-max", "claude-3"} {
+		tracker.record(name, &model.TokenUsage{PromptTokens: 10, CompletionTokens: 5})
+	}
+
+	names := tracker.ModelNames()
+	if got, want := strings.Join(names, ","), "claude-3,gpt-4o,qwen-max"; got != want {
+		t.Errorf("expected model names %s, got %s", want, got)
+	}
+	byModel := tracker.ByModel()
+	if got := byModel["claude-3"]; got.Calls != 2 || got.TotalTokens != 30 {
+		t.Errorf("expected 2 calls with 30 tokens for claude-3, got %+v", got)
+	}
+	if got := tracker.Session(); got.Calls != 4 || got.TotalTokens != 60 {
+		t.Errorf("expected 4 calls with 60 tokens in the session, got %+v", got)
+	}
+}